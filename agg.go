@@ -0,0 +1,107 @@
+package deque
+
+// AggDeque is a FIFO queue that maintains the aggregate of its elements under
+// an associative operator, such as a sum, a product, a gcd, or any monoid. It
+// supports sliding-window analytics in O(1) amortized time per operation,
+// without rescanning the window after each push.
+//
+// Elements are lifted into the aggregate type A with lift, and aggregates are
+// combined with combine, which must be associative but need not be
+// commutative: Query combines elements from front to back.
+//
+// It uses the two-stacks algorithm on top of a single Deque. The front of the
+// Deque holds suffix aggregates, and the back holds lifted elements with their
+// running aggregate. When the front runs out, the back is flipped into the
+// front in one pass.
+//
+// To create an AggDeque instance, you must use MakeAggDeque(lift, combine).
+type AggDeque[T, A any] struct {
+	lift    func(T) A
+	combine func(A, A) A
+	d       *Deque[aggEntry[T, A]]
+	// Elements before split hold suffix aggregates of the front. Elements
+	// from split on hold their lifted value, and back aggregates them.
+	split uint
+	back  A
+}
+
+type aggEntry[T, A any] struct {
+	t T
+	a A
+}
+
+// MakeAggDeque takes in the functions to lift elements into aggregates and to
+// combine two aggregates.
+func MakeAggDeque[T, A any](lift func(T) A, combine func(A, A) A) *AggDeque[T, A] {
+	return &AggDeque[T, A]{
+		lift:    lift,
+		combine: combine,
+		d:       MakeDeque[aggEntry[T, A]](),
+	}
+}
+
+// Len returns the number of elements in the AggDeque.
+func (g *AggDeque[T, A]) Len() int { return g.d.Len() }
+
+// PushBack puts the elements at the back of the AggDeque. The last argument is
+// the new back.
+func (g *AggDeque[T, A]) PushBack(ts ...T) {
+	for _, t := range ts {
+		a := g.lift(t)
+		if g.d.len() == g.split {
+			g.back = a
+		} else {
+			g.back = g.combine(g.back, a)
+		}
+		g.d.PushBack(aggEntry[T, A]{t: t, a: a})
+	}
+}
+
+// PeekFront returns the first element in the AggDeque. If it's empty, it
+// returns false.
+func (g *AggDeque[T, A]) PeekFront() (t T, ok bool) {
+	e, ok := g.d.PeekFront()
+	return e.t, ok
+}
+
+// PopFront removes the first element in the AggDeque and returns it. If it's
+// empty, returns false.
+func (g *AggDeque[T, A]) PopFront() (t T, ok bool) {
+	if g.d.Empty() {
+		return
+	}
+	if g.split == 0 {
+		g.flip()
+	}
+	g.split--
+	return g.d.PopFrontZeroUnsafe().t, true
+}
+
+// Query returns the aggregate of every element in the AggDeque, from front to
+// back. If it's empty, it returns false.
+func (g *AggDeque[T, A]) Query() (a A, ok bool) {
+	switch {
+	case g.d.Empty():
+		return
+	case g.split == 0:
+		return g.back, true
+	case g.d.len() == g.split:
+		return g.d.PeekFrontUnsafe().a, true
+	default:
+		return g.combine(g.d.PeekFrontUnsafe().a, g.back), true
+	}
+}
+
+// flip turns every element into the front, replacing lifted values with suffix
+// aggregates.
+func (g *AggDeque[T, A]) flip() {
+	d := g.d
+	n := d.len()
+	for i := n - 1; i > 0; i-- {
+		prev := &d.buf[(d.head+i-1)&d.mask]
+		prev.a = g.combine(prev.a, d.buf[(d.head+i)&d.mask].a)
+	}
+	g.split = n
+	var zero A
+	g.back = zero
+}
@@ -0,0 +1,66 @@
+package deque_test
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// TestAggDequeConcat aggregates with string concatenation, which is not
+// commutative, and checks Query against a left fold of the window across
+// random pushes and pops, draining the AggDeque to empty every so often.
+func TestAggDequeConcat(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	lift := func(v int) string { return strconv.Itoa(v) + "," }
+	g := deque.MakeAggDeque(lift, func(a, b string) string { return a + b })
+	var window []int
+	next := 0
+	for step := range 5000 {
+		switch op := r.IntN(20); {
+		case op == 0:
+			for len(window) > 0 {
+				if v, ok := g.PopFront(); !ok || v != window[0] {
+					t.Fatalf("step %d: PopFront() = %d, %t while draining, want %d, true", step, v, ok, window[0])
+				}
+				window = window[1:]
+			}
+			if _, ok := g.PopFront(); ok {
+				t.Fatalf("step %d: PopFront() on a drained AggDeque returned true", step)
+			}
+		case op < 11:
+			vs := make([]int, r.IntN(3)+1)
+			for i := range vs {
+				vs[i] = next
+				next++
+			}
+			g.PushBack(vs...)
+			window = append(window, vs...)
+		default:
+			v, ok := g.PopFront()
+			if ok != (len(window) > 0) || ok && v != window[0] {
+				t.Fatalf("step %d: PopFront() = %d, %t with window %v", step, v, ok, window)
+			}
+			if ok {
+				window = window[1:]
+			}
+		}
+
+		if g.Len() != len(window) {
+			t.Fatalf("step %d: Len() = %d, want %d", step, g.Len(), len(window))
+		}
+		var want strings.Builder
+		for _, v := range window {
+			want.WriteString(lift(v))
+		}
+		got, ok := g.Query()
+		if ok != (len(window) > 0) || got != want.String() {
+			t.Fatalf("step %d: Query() = %q, %t, want %q", step, got, ok, want.String())
+		}
+		if v, ok := g.PeekFront(); ok != (len(window) > 0) || ok && v != window[0] {
+			t.Fatalf("step %d: PeekFront() = %d, %t with window %v", step, v, ok, window)
+		}
+	}
+}
@@ -0,0 +1,1257 @@
+package deque
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"sort"
+)
+
+const (
+	blockShift = 7
+	// blockLen is the number of elements in each block of a BlockDeque.
+	blockLen  = 1 << blockShift
+	blockMask = blockLen - 1
+)
+
+// BlockDeque is a double-ended queue made of fixed-size blocks, like C++'s
+// std::deque. It has most of Deque's methods, but it never copies its elements
+// to grow: pushing past the last block only allocates a new block of 128
+// elements and records it in a small Deque of block pointers. This avoids the
+// latency spikes and the transient memory use of reallocating a Deque of
+// millions of elements.
+//
+// As a consequence, pushing and popping never move elements, and pointers
+// returned by PtrAt stay valid until the element is removed. Insert, Delete,
+// and the other methods that edit or reorder elements in place do move them,
+// shifting only the elements between the edit and the nearer end. Blocks that
+// become empty are kept in a free list and reused by later pushes. Call Shrink
+// to release them.
+//
+// Indexing costs one more indirection than in a Deque, and the capacity grows
+// one block at a time instead of doubling. Since the elements are not stored
+// in at most two slices, the method set differs from Deque's where that shows:
+//
+//   - There is no AsSlices. Blocks, and a BlockView's Blocks, yield one slice
+//     per block instead of returning two.
+//   - MakeContiguous copies the elements into a new slice instead of returning
+//     one that shares memory with the BlockDeque.
+//   - Sorting is done in place by the sort package instead of slices.
+//   - Cursors and iterators that remove elements are BlockCursor and
+//     BlockIterator instead of Cursor and Iterator.
+//   - The package functions that constrain the element type, such as Sort,
+//     Equal, or InsertSorted, only take a Deque. Call the corresponding Func
+//     methods with cmp.Compare or == instead.
+//
+// To create a BlockDeque instance, you must use one of the available
+// constructors, MakeBlockDeque() or MakeBlockDequeWithCapacity(cap).
+//
+// Iterators are fail-fast, like those of a Deque.
+type BlockDeque[T any] struct {
+	blocks *Deque[*[blockLen]T]
+	free   []*[blockLen]T
+	// off is the index of the first element in the first block, and n is the
+	// number of elements, so the blocks cover [0, off+n).
+	off, n uint
+	// mod counts structural modifications, so iterators can detect them.
+	mod uint
+	// maxLen is the maximum length, or 0 for none. onEvict is called with
+	// every element evicted to respect it.
+	maxLen  uint
+	onEvict func(T)
+}
+
+/*****************************************************************************
+ * CONSTRUCTORS
+ *****************************************************************************/
+
+// MakeBlockDeque creates an empty BlockDeque. Blocks are allocated by the
+// first pushes.
+func MakeBlockDeque[T any]() *BlockDeque[T] {
+	return &BlockDeque[T]{blocks: MakeDeque[*[blockLen]T]()}
+}
+
+// MakeBlockDequeWithCapacity takes in the desired capacity and allocates
+// enough blocks to hold it. Note that the capacity is rounded up to a whole
+// number of blocks. Returns an error if passed a negative value.
+func MakeBlockDequeWithCapacity[T any](capacity int) (*BlockDeque[T], error) {
+	if capacity < 0 {
+		return nil, ErrNegativeCapacity
+	}
+	d := MakeBlockDeque[T]()
+	_ = d.Reserve(capacity)
+	return d, nil
+}
+
+/*****************************************************************************
+ * DEQUE API
+ *****************************************************************************/
+
+// Len returns the number of elements in the BlockDeque or 0 if nil.
+func (d *BlockDeque[T]) Len() int {
+	if d == nil {
+		return 0
+	}
+	return int(d.n)
+}
+
+// Empty returns whether the BlockDeque is empty.
+func (d *BlockDeque[T]) Empty() bool { return d.n == 0 }
+
+// Full returns whether every slot of the allocated blocks holds an element, so
+// that pushing to either end allocates a block, unless the BlockDeque is at its
+// maximum length and evicts instead. Since the first and last blocks may be
+// partially used, pushing to one end may allocate before it's full.
+func (d *BlockDeque[T]) Full() bool { return d.n == d.cap() }
+
+// PushBack takes in a variable number of arguments and puts them at the back
+// of the BlockDeque. The last argument is the new back of the list. It
+// allocates at most one block for every 128 elements, and never copies the
+// existing elements.
+//
+// If the BlockDeque has a maximum length, PushBack evicts elements from the
+// front to make room instead of growing past it.
+func (d *BlockDeque[T]) PushBack(ts ...T) {
+	for _, t := range ts {
+		d.makeRoomBack()
+		*d.pushBack() = t
+	}
+	d.mod++
+}
+
+// PushFront takes in a variable number of arguments and puts them at the front
+// of the BlockDeque. The last argument is the new front of the list. It
+// allocates at most one block for every 128 elements, and never copies the
+// existing elements.
+//
+// If the BlockDeque has a maximum length, PushFront evicts elements from the
+// back to make room instead of growing past it.
+func (d *BlockDeque[T]) PushFront(ts ...T) {
+	for _, t := range ts {
+		d.makeRoomFront()
+		*d.pushFront() = t
+	}
+	d.mod++
+}
+
+// PeekBack returns the last element in the BlockDeque. If it's empty, it
+// returns false.
+func (d *BlockDeque[T]) PeekBack() (t T, ok bool) {
+	if d.Empty() {
+		return
+	}
+	return d.PeekBackUnsafe(), true
+}
+
+// PeekBackUnsafe returns the last element in the BlockDeque. Calling it on an
+// empty BlockDeque panics or returns garbage.
+func (d *BlockDeque[T]) PeekBackUnsafe() T {
+	return *d.ptr(d.n - 1)
+}
+
+// PeekFront returns the first element in the BlockDeque. If it's empty, it
+// returns false.
+func (d *BlockDeque[T]) PeekFront() (t T, ok bool) {
+	if d.Empty() {
+		return
+	}
+	return d.PeekFrontUnsafe(), true
+}
+
+// PeekFrontUnsafe returns the first element in the BlockDeque. Calling it on
+// an empty BlockDeque panics or returns garbage.
+func (d *BlockDeque[T]) PeekFrontUnsafe() T {
+	return *d.ptr(0)
+}
+
+// PopBack removes the last element in the BlockDeque and returns it. If it's
+// empty, returns false. Like Deque's PopBack, it does not zero the element, so
+// prefer PopBackZero if your elements have references.
+func (d *BlockDeque[T]) PopBack() (t T, ok bool) {
+	if d.Empty() {
+		return
+	}
+	return d.PopBackUnsafe(), true
+}
+
+// PopBackZero removes the last element in the BlockDeque, zeroes its slot, and
+// returns it. If it's empty, returns false.
+func (d *BlockDeque[T]) PopBackZero() (t T, ok bool) {
+	if d.Empty() {
+		return
+	}
+	return d.PopBackZeroUnsafe(), true
+}
+
+// PopBackShrink removes the last element in the BlockDeque and returns it. If
+// it's empty, false is returned. If the BlockDeque is at <= 25% capacity, free
+// blocks are released until it is at <= 50% capacity.
+func (d *BlockDeque[T]) PopBackShrink() (t T, ok bool) {
+	t, ok = d.PopBack()
+	d.shrinkFree()
+	return
+}
+
+// PopBackUnsafe removes the last element in the BlockDeque and returns it
+// without zeroing it. Calling it on an empty BlockDeque panics or leads to
+// undefined behavior from then on.
+func (d *BlockDeque[T]) PopBackUnsafe() T {
+	result := *d.ptr(d.n - 1)
+	d.n--
+	d.trimBack()
+	d.mod++
+	return result
+}
+
+// PopBackZeroUnsafe removes the last element in the BlockDeque, zeroes its
+// slot, and returns it. Calling it on an empty BlockDeque panics or leads to
+// undefined behavior from then on.
+func (d *BlockDeque[T]) PopBackZeroUnsafe() T {
+	var zero T
+	p := d.ptr(d.n - 1)
+	result := *p
+	*p = zero
+	d.n--
+	d.trimBack()
+	d.mod++
+	return result
+}
+
+// PopFront removes the first element in the BlockDeque and returns it. If it's
+// empty, returns false. Like Deque's PopFront, it does not zero the element,
+// so prefer PopFrontZero if your elements have references.
+func (d *BlockDeque[T]) PopFront() (t T, ok bool) {
+	if d.Empty() {
+		return
+	}
+	return d.PopFrontUnsafe(), true
+}
+
+// PopFrontZero removes the first element in the BlockDeque, zeroes its slot,
+// and returns it. If it's empty, returns false.
+func (d *BlockDeque[T]) PopFrontZero() (t T, ok bool) {
+	if d.Empty() {
+		return
+	}
+	return d.PopFrontZeroUnsafe(), true
+}
+
+// PopFrontShrink removes the first element in the BlockDeque and returns it.
+// If it's empty, false is returned. If the BlockDeque is at <= 25% capacity,
+// free blocks are released until it is at <= 50% capacity.
+func (d *BlockDeque[T]) PopFrontShrink() (t T, ok bool) {
+	t, ok = d.PopFront()
+	d.shrinkFree()
+	return
+}
+
+// PopFrontUnsafe removes the first element in the BlockDeque and returns it
+// without zeroing it. Calling it on an empty BlockDeque panics or leads to
+// undefined behavior from then on.
+func (d *BlockDeque[T]) PopFrontUnsafe() T {
+	result := *d.ptr(0)
+	d.dropFront(1)
+	d.mod++
+	return result
+}
+
+// PopFrontZeroUnsafe removes the first element in the BlockDeque, zeroes its
+// slot, and returns it. Calling it on an empty BlockDeque panics or leads to
+// undefined behavior from then on.
+func (d *BlockDeque[T]) PopFrontZeroUnsafe() T {
+	var zero T
+	p := d.ptr(0)
+	result := *p
+	*p = zero
+	d.dropFront(1)
+	d.mod++
+	return result
+}
+
+// DropFront removes the n first elements of the BlockDeque without clearing
+// references. It takes O(n/128), releasing the emptied blocks. If the
+// BlockDeque has fewer than n elements, it drops every element. If n is
+// negative, no element is dropped.
+func (d *BlockDeque[T]) DropFront(n int) {
+	if n >= 0 {
+		d.dropFront(min(uint(n), d.n))
+		d.mod++
+	}
+}
+
+// DropFrontZero removes the n first elements of the BlockDeque in O(n) and
+// clears references, allowing garbage collection to occur. If the BlockDeque
+// has fewer than n elements, it drops every element.
+func (d *BlockDeque[T]) DropFrontZero(n int) {
+	if n >= 0 {
+		n := min(uint(n), d.n)
+		d.zero(0, n)
+		d.dropFront(n)
+		d.mod++
+	}
+}
+
+// DropBack removes the n last elements of the BlockDeque without clearing
+// references. It takes O(n/128), releasing the emptied blocks. If the
+// BlockDeque has fewer than n elements, it drops every element. If n is
+// negative, no element is dropped.
+func (d *BlockDeque[T]) DropBack(n int) {
+	if n >= 0 {
+		d.n -= min(uint(n), d.n)
+		d.trimBack()
+		d.mod++
+	}
+}
+
+// DropBackZero removes the n last elements of the BlockDeque in O(n) and
+// clears references, allowing garbage collection to occur. If the BlockDeque
+// has fewer than n elements, it drops every element.
+func (d *BlockDeque[T]) DropBackZero(n int) {
+	if n >= 0 {
+		n := min(uint(n), d.n)
+		d.zero(d.n-n, d.n)
+		d.n -= n
+		d.trimBack()
+		d.mod++
+	}
+}
+
+/*****************************************************************************
+ * RING API
+ *****************************************************************************/
+
+// MaxLen returns the maximum length of the BlockDeque, or 0 if it has none.
+func (d *BlockDeque[T]) MaxLen() int { return int(d.maxLen) }
+
+// SetMaxLen sets the maximum length of the BlockDeque. If the BlockDeque holds
+// more than n elements, the excess is evicted from the front. Passing 0
+// removes the limit. Returns an error if n is negative.
+func (d *BlockDeque[T]) SetMaxLen(n int) error {
+	if n < 0 {
+		return ErrInvalidMaxLen
+	}
+	d.maxLen = uint(n)
+	for n != 0 && d.n > d.maxLen {
+		d.evict(d.PopFrontZeroUnsafe())
+	}
+	return nil
+}
+
+// OnEvict registers a function to be called with every element evicted by a
+// push past the maximum length, in eviction order. Passing nil unregisters
+// it. f must not modify the BlockDeque.
+func (d *BlockDeque[T]) OnEvict(f func(T)) { d.onEvict = f }
+
+// makeRoomBack evicts the first element if the BlockDeque is at its maximum
+// length, to make room for a push at the back.
+func (d *BlockDeque[T]) makeRoomBack() {
+	if d.maxLen != 0 && d.n >= d.maxLen {
+		d.evict(d.PopFrontZeroUnsafe())
+	}
+}
+
+// makeRoomFront evicts the last element if the BlockDeque is at its maximum
+// length, to make room for a push at the front.
+func (d *BlockDeque[T]) makeRoomFront() {
+	if d.maxLen != 0 && d.n >= d.maxLen {
+		d.evict(d.PopBackZeroUnsafe())
+	}
+}
+
+func (d *BlockDeque[T]) evict(t T) {
+	if d.onEvict != nil {
+		d.onEvict(t)
+	}
+}
+
+/*****************************************************************************
+ * SLICE API
+ *****************************************************************************/
+
+// Cap returns the number of elements the allocated blocks can hold, including
+// the free ones. Since the first and last blocks may be partially used,
+// pushing fewer than Cap() - Len() elements may still allocate a block.
+func (d *BlockDeque[T]) Cap() int { return int(d.cap()) }
+func (d *BlockDeque[T]) cap() uint {
+	return (d.blocks.len() + uint(len(d.free))) << blockShift
+}
+
+// Resize takes in the minimum desired capacity, rounds it up to a whole number
+// of blocks, and allocates or releases free blocks. Elements never move.
+//
+// It returns an error if the new capacity matches the old, or if the new
+// capacity cannot hold the existing elements, or if minCapacity is negative.
+func (d *BlockDeque[T]) Resize(minCapacity int) error {
+	if minCapacity < 0 {
+		return ErrNegativeCapacity
+	}
+	if uint(minCapacity) < d.n {
+		return ErrNotEnoughCapacity
+	}
+	return d.resize(max(blocksFor(uint(minCapacity)), d.blocks.len()))
+}
+
+// Internal implementation for Resize, Reserve, and Shrink. It takes in the
+// total number of blocks, which must cover the used ones.
+func (d *BlockDeque[T]) resize(nblocks uint) error {
+	have := d.blocks.len() + uint(len(d.free))
+	if nblocks == have {
+		return ErrSameCapacity
+	}
+	for ; have > nblocks; have-- {
+		d.free[len(d.free)-1] = nil
+		d.free = d.free[:len(d.free)-1]
+	}
+	for ; have < nblocks; have++ {
+		d.free = append(d.free, new([blockLen]T))
+	}
+	return nil
+}
+
+// Reserve ensures there's enough capacity to add at least n more elements to
+// either end of the BlockDeque, allocating blocks if necessary. It returns an
+// error if n is negative.
+func (d *BlockDeque[T]) Reserve(n int) error {
+	if n < 0 {
+		return ErrNegativeCapacity
+	}
+	need := blocksFor(uint(n))
+	if uint(len(d.free)) < need {
+		_ = d.resize(d.blocks.len() + need)
+	}
+	_ = d.blocks.Reserve(int(need))
+	return nil
+}
+
+// Shrink releases every free block and returns the new BlockDeque's capacity.
+func (d *BlockDeque[T]) Shrink() uint {
+	_ = d.resize(d.blocks.len())
+	d.free = nil
+	d.blocks.Shrink()
+	return d.cap()
+}
+
+// Blocks returns an iterator over the elements in order, as contiguous slices
+// that don't copy them: one per block, with the first and last ones trimmed to
+// the elements. It takes the place of Deque's AsSlices, which returns at most
+// two slices. The slices share memory with the BlockDeque, so writes through
+// them are visible in the BlockDeque. Panics if the BlockDeque is structurally
+// modified during iteration.
+func (d *BlockDeque[T]) Blocks() iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if d == nil {
+			return
+		}
+		mod := d.mod
+		for i := uint(0); i < d.n; {
+			s := d.span(i)
+			if !yield(s) {
+				return
+			}
+			d.checkMod(mod)
+			i += uint(len(s))
+		}
+	}
+}
+
+// MakeContiguous allocates a slice to hold every BlockDeque element and copies
+// them. Unlike Deque's MakeContiguous, the elements can't be made contiguous
+// in place, so the slice doesn't share memory with the BlockDeque. It has the
+// same semantics as MakeSliceCopy.
+func (d *BlockDeque[T]) MakeContiguous() []T { return d.MakeSliceCopy() }
+
+// MakeSliceCopy allocates a slice to hold every BlockDeque element and copies
+// them.
+func (d *BlockDeque[T]) MakeSliceCopy() []T {
+	s := make([]T, d.n)
+	_ = d.CopySlice(0, s)
+	return s
+}
+
+// MakeSliceIndexCopy allocates a slice and copies the contents from the start
+// index (inclusive) to the end index (non-inclusive). It panics with invalid
+// indexes.
+func (d *BlockDeque[T]) MakeSliceIndexCopy(start, end int) []T {
+	d.checkRange(start, end)
+	s := make([]T, end-start)
+	_ = d.CopySlice(start, s)
+	return s
+}
+
+// MakeSliceIndexCopyWithCapacity allocates a slice with the given capacity and
+// copies the contents from the start index (inclusive) to the end index
+// (non-inclusive), keeping the extra capacity filled with zeroes. It panics
+// with invalid indexes, or if the capacity is lower than end-start.
+func (d *BlockDeque[T]) MakeSliceIndexCopyWithCapacity(start, end, capacity int) []T {
+	d.checkRange(start, end)
+	s := make([]T, capacity)
+	_ = d.CopySlice(start, s[:end-start])
+	return s
+}
+
+// CopySlice has the same semantics as the copy() built-in function. It copies
+// elements in the BlockDeque starting at the start index up until the buffer
+// is full or the BlockDeque is over, whichever happens first, and returns the
+// number of elements copied. Panics if start is out of bounds.
+func (d *BlockDeque[T]) CopySlice(start int, buf []T) int {
+	d.checkRange(start, d.Len())
+	n := 0
+	for i := uint(start); i < d.n && n < len(buf); {
+		s := d.span(i)
+		c := copy(buf[n:], s)
+		n += c
+		i += uint(c)
+	}
+	return n
+}
+
+// At indexes into the i-th position in the BlockDeque. Panics if out of
+// bounds.
+func (d *BlockDeque[T]) At(i int) T {
+	d.checkBounds(i)
+	return d.AtUnsafe(i)
+}
+
+// AtUnsafe indexes into the i-th position in the BlockDeque. It panics or
+// returns garbage if i is out of bounds.
+func (d *BlockDeque[T]) AtUnsafe(i int) T {
+	return *d.ptr(uint(i))
+}
+
+// Set writes t to the i-th position in the BlockDeque. Panics if out of
+// bounds.
+func (d *BlockDeque[T]) Set(i int, t T) {
+	d.checkBounds(i)
+	d.SetUnsafe(i, t)
+}
+
+// SetUnsafe writes t to the i-th position in the BlockDeque. It panics or
+// writes to a slot outside the BlockDeque if i is out of bounds.
+func (d *BlockDeque[T]) SetUnsafe(i int, t T) {
+	*d.ptr(uint(i)) = t
+}
+
+// PtrAt returns a pointer to the i-th element in the BlockDeque. Panics if out
+// of bounds. Since pushing and popping never move elements, the pointer stays
+// valid until the element is popped, dropped, or cleared, or until a method
+// that edits or reorders elements in place, such as Insert, moves it.
+func (d *BlockDeque[T]) PtrAt(i int) *T {
+	d.checkBounds(i)
+	return d.ptr(uint(i))
+}
+
+// FrontPtr returns a pointer to the first element in the BlockDeque, or nil if
+// it's empty. It stays valid as described in PtrAt.
+func (d *BlockDeque[T]) FrontPtr() *T {
+	if d.Empty() {
+		return nil
+	}
+	return d.ptr(0)
+}
+
+// BackPtr returns a pointer to the last element in the BlockDeque, or nil if
+// it's empty. It stays valid as described in PtrAt.
+func (d *BlockDeque[T]) BackPtr() *T {
+	if d.Empty() {
+		return nil
+	}
+	return d.ptr(d.n - 1)
+}
+
+// PushBackSlot pushes a zero value at the back of the BlockDeque and returns a
+// pointer to it, so that a large element can be built in place. It has the
+// same semantics as PushBack, including eviction, and the pointer stays valid
+// as described in PtrAt.
+func (d *BlockDeque[T]) PushBackSlot() *T {
+	var zero T
+	d.makeRoomBack()
+	p := d.pushBack()
+	*p = zero
+	d.mod++
+	return p
+}
+
+// PushFrontSlot pushes a zero value at the front of the BlockDeque and returns
+// a pointer to it, so that a large element can be built in place. It has the
+// same semantics as PushFront, including eviction, and the pointer stays valid
+// as described in PtrAt.
+func (d *BlockDeque[T]) PushFrontSlot() *T {
+	var zero T
+	d.makeRoomFront()
+	p := d.pushFront()
+	*p = zero
+	d.mod++
+	return p
+}
+
+// Swap swaps the elements in the i-th and j-th indexes. Panics if out of
+// bounds.
+func (d *BlockDeque[T]) Swap(i, j int) {
+	d.checkBounds(i)
+	d.checkBounds(j)
+	d.SwapUnsafe(i, j)
+}
+
+// SwapUnsafe swaps the elements in the i-th and j-th indexes. It panics or
+// swaps slots outside the BlockDeque if out of bounds.
+func (d *BlockDeque[T]) SwapUnsafe(i, j int) {
+	p, q := d.ptr(uint(i)), d.ptr(uint(j))
+	*p, *q = *q, *p
+}
+
+// Insert inserts the elements at index i, in order, so that the first one ends
+// up at index i. It has the same semantics as slices.Insert, so it panics if
+// i is out of range, but it moves only the elements between i and the nearer
+// end, allocating at most one block for every 128 elements. If the BlockDeque
+// has a maximum length, the overflow is evicted from the front.
+func (d *BlockDeque[T]) Insert(i int, ts ...T) {
+	if i < 0 || i > d.Len() {
+		panic(fmt.Sprintf("deque: insert index %d out of range with length %d", i, d.Len()))
+	}
+	d.insert(uint(i), ts)
+}
+
+// Delete removes the elements from index i (inclusive) to index j
+// (non-inclusive). It has the same semantics as slices.Delete, so it panics
+// if the indexes are invalid, but it moves only the elements between the
+// deleted ones and the nearer end. The vacated slots are zeroed, and emptied
+// blocks go to the free list.
+func (d *BlockDeque[T]) Delete(i, j int) {
+	d.checkRange(i, j)
+	d.delete(uint(i), uint(j))
+}
+
+// Replace replaces the elements from index i (inclusive) to index j
+// (non-inclusive) with the given elements. It has the same semantics as
+// slices.Replace, so it panics if the indexes are invalid. Like Insert and
+// Delete, it moves only the elements between the replaced ones and the nearer
+// end, and zeroes vacated slots.
+func (d *BlockDeque[T]) Replace(i, j int, ts ...T) {
+	d.checkRange(i, j)
+	k := min(j-i, len(ts))
+	for x, t := range ts[:k] {
+		d.SetUnsafe(i+x, t)
+	}
+	if len(ts) > k {
+		d.insert(uint(j), ts[k:])
+	} else {
+		d.delete(uint(i+k), uint(j))
+	}
+}
+
+// RemoveAt removes the i-th element and returns it. It moves only the elements
+// between i and the nearer end, and zeroes the vacated slot. Panics if out of
+// bounds.
+func (d *BlockDeque[T]) RemoveAt(i int) T {
+	d.checkBounds(i)
+	t := d.AtUnsafe(i)
+	d.delete(uint(i), uint(i)+1)
+	return t
+}
+
+// ClearLazy empties the BlockDeque without zeroing the elements, moving every
+// block to the free list in O(Len()/128).
+func (d *BlockDeque[T]) ClearLazy() {
+	d.dropFront(d.n)
+	d.mod++
+}
+
+// ClearEager empties the BlockDeque in O(Len()), zeroing existing elements
+// and moving every block to the free list. This is useful for reusing a
+// BlockDeque with references.
+func (d *BlockDeque[T]) ClearEager() {
+	d.zero(0, d.n)
+	d.dropFront(d.n)
+	d.mod++
+}
+
+// ContainsFunc returns whether an element satisfying f is in the BlockDeque.
+// It has the same semantics as slices.ContainsFunc.
+func (d *BlockDeque[T]) ContainsFunc(f func(T) bool) bool {
+	return d.IndexFunc(f) != -1
+}
+
+// EqualFunc returns whether both BlockDeques have the same length and the same
+// elements in the same order, as determined by f. Two nil BlockDeques are
+// equal, but an empty BlockDeque and nil are not, like Deque's EqualFunc.
+func (d1 *BlockDeque[T]) EqualFunc(d2 *BlockDeque[T], f func(T, T) bool) bool {
+	if d1 == nil || d2 == nil {
+		return d1 == d2
+	}
+	if d1.n != d2.n {
+		return false
+	}
+	for i := uint(0); i < d1.n; {
+		a, b := d1.span(i), d2.span(i)
+		c := min(len(a), len(b))
+		if !slices.EqualFunc(a[:c], b[:c], f) {
+			return false
+		}
+		i += uint(c)
+	}
+	return true
+}
+
+// IndexFunc returns the index of the first element that satisfies f in the
+// BlockDeque or -1 if none do. It has the same semantics as slices.IndexFunc.
+func (d *BlockDeque[T]) IndexFunc(f func(T) bool) int {
+	for i := uint(0); i < d.n; {
+		s := d.span(i)
+		for j, t := range s {
+			if f(t) {
+				return int(i) + j
+			}
+		}
+		i += uint(len(s))
+	}
+	return -1
+}
+
+// ForEach takes in a function that returns a bool and calls it in order for
+// every element, or until the first call that returns false. Panics if f
+// structurally modifies the BlockDeque.
+func (d *BlockDeque[T]) ForEach(f func(T) bool) {
+	for t := range d.Iter() {
+		if !f(t) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over index-value pairs in order. It has the same
+// semantics as slices.All. Panics if the BlockDeque is structurally modified
+// during iteration.
+func (d *BlockDeque[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		if d == nil {
+			return
+		}
+		mod := d.mod
+		for i := uint(0); i < d.n; {
+			s := d.span(i)
+			for j, t := range s {
+				if !yield(int(i)+j, t) {
+					return
+				}
+				d.checkMod(mod)
+			}
+			i += uint(len(s))
+		}
+	}
+}
+
+// RotateLeft rotates the BlockDeque k positions to the left, so that the
+// element at index k becomes the first one. It takes O(min(k, n-k)) by moving
+// elements between the ends, wrapping k around the length. A negative k
+// rotates to the right.
+func (d *BlockDeque[T]) RotateLeft(k int) {
+	n := d.Len()
+	if n == 0 {
+		return
+	}
+	k %= n
+	if k < 0 {
+		k += n
+	}
+	if k <= n-k {
+		for range k {
+			t := d.PopFrontZeroUnsafe()
+			*d.pushBack() = t
+		}
+	} else {
+		for range n - k {
+			t := d.PopBackZeroUnsafe()
+			*d.pushFront() = t
+		}
+	}
+	d.mod++
+}
+
+// RotateRight rotates the BlockDeque k positions to the right, so that the
+// element at index n-k becomes the first one. It has the same semantics as
+// RotateLeft(-k).
+func (d *BlockDeque[T]) RotateRight(k int) {
+	n := d.Len()
+	if n == 0 {
+		return
+	}
+	d.RotateLeft(n - k%n)
+}
+
+// Reverse reverses the elements of the BlockDeque in place. It has the same
+// semantics as slices.Reverse.
+func (d *BlockDeque[T]) Reverse() {
+	for i, j := 0, d.Len()-1; i < j; i, j = i+1, j-1 {
+		d.SwapUnsafe(i, j)
+	}
+}
+
+// Retain keeps only the elements for which f returns true, in order, and
+// returns how many were removed. It makes a single pass across the blocks and
+// zeroes the freed slots, allowing garbage collection to occur.
+func (d *BlockDeque[T]) Retain(f func(T) bool) int {
+	n := d.n
+	var w uint
+	for r := uint(0); r < n; {
+		for _, t := range d.span(r) {
+			if f(t) {
+				if w != r {
+					*d.ptr(w) = t
+				}
+				w++
+			}
+			r++
+		}
+	}
+	if w != n {
+		d.DropBackZero(int(n - w))
+	}
+	return int(n - w)
+}
+
+// DeleteFunc removes the elements for which f returns true and returns how
+// many were removed. It has the same semantics as slices.DeleteFunc, and the
+// same performance as Retain.
+func (d *BlockDeque[T]) DeleteFunc(f func(T) bool) int {
+	return d.Retain(func(t T) bool { return !f(t) })
+}
+
+// CompactFunc replaces consecutive runs of elements that compare equal with
+// the first one, and returns how many were removed. It has the same semantics
+// as slices.CompactFunc, so eq is called with each element and the one before
+// it, in that order, and the same performance as Retain.
+func (d *BlockDeque[T]) CompactFunc(eq func(T, T) bool) int {
+	first := true
+	var prev T
+	return d.Retain(func(t T) bool {
+		keep := first || !eq(t, prev)
+		first = false
+		prev = t
+		return keep
+	})
+}
+
+/*****************************************************************************
+ * SORT API
+ *****************************************************************************/
+
+// SortFunc sorts the BlockDeque in place in ascending order as determined by
+// cmp. It has the same semantics as slices.SortFunc, but sorts across the
+// blocks with sort.Sort, so it allocates no buffer.
+func (d *BlockDeque[T]) SortFunc(cmp func(a, b T) int) {
+	sort.Sort(blockSorter[T]{d, cmp})
+}
+
+// SortStableFunc sorts the BlockDeque in place in ascending order as
+// determined by cmp, keeping the original order of equal elements. It has the
+// same semantics as slices.SortStableFunc, but sorts across the blocks with
+// sort.Stable, so it allocates no buffer.
+func (d *BlockDeque[T]) SortStableFunc(cmp func(a, b T) int) {
+	sort.Stable(blockSorter[T]{d, cmp})
+}
+
+// IsSortedFunc returns whether the BlockDeque is sorted in ascending order as
+// determined by cmp. It has the same semantics as slices.IsSortedFunc.
+func (d *BlockDeque[T]) IsSortedFunc(cmp func(a, b T) int) bool {
+	var last T
+	for i := uint(0); i < d.n; {
+		s := d.span(i)
+		if i > 0 && cmp(s[0], last) < 0 || !slices.IsSortedFunc(s, cmp) {
+			return false
+		}
+		last = s[len(s)-1]
+		i += uint(len(s))
+	}
+	return true
+}
+
+// PartitionPoint returns the index of the first element for which pred is
+// false, assuming the BlockDeque is partitioned so that pred is true for a
+// prefix and false for the rest, like Rust's slice::partition_point. If pred
+// is true for every element, it returns d.Len().
+func (d *BlockDeque[T]) PartitionPoint(pred func(T) bool) int {
+	return sort.Search(d.Len(), func(i int) bool { return !pred(*d.ptr(uint(i))) })
+}
+
+// InsertSortedFunc inserts t into a BlockDeque sorted as determined by cmp,
+// keeping it sorted, and returns its index. It is inserted after any equal
+// elements. Like Insert, it moves only the elements between the index and the
+// nearer end. cmp has the same semantics as the one in slices.SortFunc.
+//
+// If the BlockDeque has a maximum length, the overflow is evicted from the
+// front, and the returned index accounts for it. If t itself is evicted
+// because it sorts before every other element, it returns -1.
+func (d *BlockDeque[T]) InsertSortedFunc(t T, cmp func(a, b T) int) int {
+	i := d.PartitionPoint(func(e T) bool { return cmp(e, t) <= 0 })
+	evicted := d.insert(uint(i), []T{t})
+	if uint(i) < evicted {
+		return -1
+	}
+	return i - int(evicted)
+}
+
+// blockSorter sorts a BlockDeque in place with the sort package.
+type blockSorter[T any] struct {
+	d   *BlockDeque[T]
+	cmp func(a, b T) int
+}
+
+func (s blockSorter[T]) Len() int { return s.d.Len() }
+
+func (s blockSorter[T]) Less(i, j int) bool {
+	return s.cmp(*s.d.ptr(uint(i)), *s.d.ptr(uint(j))) < 0
+}
+
+func (s blockSorter[T]) Swap(i, j int) { s.d.SwapUnsafe(i, j) }
+
+/*****************************************************************************
+ * ITER API
+ *****************************************************************************/
+
+// Iter returns an iterator over values only in order. Panics if the BlockDeque
+// is structurally modified during iteration.
+func (d *BlockDeque[T]) Iter() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, t := range d.All() {
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// RIter returns an iterator over values only in reverse order, from back to
+// front. Panics if the BlockDeque is structurally modified during iteration.
+func (d *BlockDeque[T]) RIter() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, t := range d.Backward() {
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over index-value pairs in reverse order, from
+// back to front. It has the same semantics as slices.Backward. Panics if the
+// BlockDeque is structurally modified during iteration.
+func (d *BlockDeque[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		if d == nil {
+			return
+		}
+		mod := d.mod
+		for i := d.n; i > 0; {
+			s := d.rspan(i)
+			i -= uint(len(s))
+			for j := len(s) - 1; j >= 0; j-- {
+				if !yield(int(i)+j, s[j]) {
+					return
+				}
+				d.checkMod(mod)
+			}
+		}
+	}
+}
+
+// IterPopFront returns an iterator that pops elements from the front and
+// yields them, until the BlockDeque is empty. It has the same semantics as
+// Deque's IterPopFront.
+func (d *BlockDeque[T]) IterPopFront() iter.Seq[T] {
+	return d.iterPop((*BlockDeque[T]).PopFrontUnsafe)
+}
+
+// IterPopFrontZero is IterPopFront, but zeroes the popped slots like
+// PopFrontZero, allowing garbage collection to occur.
+func (d *BlockDeque[T]) IterPopFrontZero() iter.Seq[T] {
+	return d.iterPop((*BlockDeque[T]).PopFrontZeroUnsafe)
+}
+
+// IterPopBack returns an iterator that pops elements from the back and yields
+// them, until the BlockDeque is empty. It has the same semantics as Deque's
+// IterPopBack.
+func (d *BlockDeque[T]) IterPopBack() iter.Seq[T] {
+	return d.iterPop((*BlockDeque[T]).PopBackUnsafe)
+}
+
+// IterPopBackZero is IterPopBack, but zeroes the popped slots like
+// PopBackZero, allowing garbage collection to occur.
+func (d *BlockDeque[T]) IterPopBackZero() iter.Seq[T] {
+	return d.iterPop((*BlockDeque[T]).PopBackZeroUnsafe)
+}
+
+// BlockIterator is a forward iterator over a BlockDeque that allows removing
+// elements during iteration, like Iterator is for a Deque. It panics if the
+// BlockDeque is structurally modified other than through the BlockIterator.
+type BlockIterator[T any] struct {
+	d     *BlockDeque[T]
+	next  uint
+	cur   uint
+	valid bool
+	mod   uint
+}
+
+// Iterator returns a BlockIterator positioned before the first element.
+func (d *BlockDeque[T]) Iterator() *BlockIterator[T] {
+	return &BlockIterator[T]{d: d, mod: d.mod}
+}
+
+// Next advances the BlockIterator to the next element and returns whether
+// there is one.
+func (it *BlockIterator[T]) Next() bool {
+	it.d.checkMod(it.mod)
+	it.valid = it.next < it.d.n
+	if it.valid {
+		it.cur = it.next
+		it.next++
+	}
+	return it.valid
+}
+
+// Index returns the index of the current element. Panics if there is none.
+func (it *BlockIterator[T]) Index() int {
+	it.checkValid()
+	return int(it.cur)
+}
+
+// Value returns the current element. Panics if there is none.
+func (it *BlockIterator[T]) Value() T {
+	it.checkValid()
+	return *it.d.ptr(it.cur)
+}
+
+// Set overwrites the current element. Panics if there is none.
+func (it *BlockIterator[T]) Set(t T) {
+	it.checkValid()
+	*it.d.ptr(it.cur) = t
+}
+
+// Remove removes the current element from the BlockDeque, moving the elements
+// between it and the nearer end and zeroing the vacated slot. There is no
+// current element until the next call to Next. Panics if there is none.
+func (it *BlockIterator[T]) Remove() {
+	it.checkValid()
+	it.d.delete(it.cur, it.cur+1)
+	it.mod = it.d.mod
+	it.next--
+	it.valid = false
+}
+
+func (it *BlockIterator[T]) checkValid() {
+	it.d.checkMod(it.mod)
+	if !it.valid {
+		panic("deque: BlockIterator has no current element")
+	}
+}
+
+func (d *BlockDeque[T]) iterPop(pop func(*BlockDeque[T]) T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if d == nil {
+			return
+		}
+		for !d.Empty() {
+			if !yield(pop(d)) {
+				return
+			}
+		}
+	}
+}
+
+/*****************************************************************************
+ * HELPERS
+ *****************************************************************************/
+
+// blocksFor returns the number of blocks needed to hold n elements.
+func blocksFor(n uint) uint { return (n + blockMask) >> blockShift }
+
+// ptr returns a pointer to the i-th element. i must be within bounds.
+func (d *BlockDeque[T]) ptr(i uint) *T {
+	j := d.off + i
+	return &d.blocks.AtUnsafe(int(j >> blockShift))[j&blockMask]
+}
+
+// span returns the elements from index i to the end of its block, or to the
+// back if it comes first. i must be within bounds.
+func (d *BlockDeque[T]) span(i uint) []T {
+	j := d.off + i
+	lo := j & blockMask
+	hi := min(blockLen, lo+d.n-i)
+	return d.blocks.AtUnsafe(int(j >> blockShift))[lo:hi]
+}
+
+// rspan returns the elements from the start of the block of index i-1, or
+// from the front if it comes later, to index i. i must be within (0, d.n].
+func (d *BlockDeque[T]) rspan(i uint) []T {
+	j := d.off + i - 1
+	hi := j&blockMask + 1
+	lo := hi - min(hi, i)
+	return d.blocks.AtUnsafe(int(j >> blockShift))[lo:hi]
+}
+
+// pushBack makes room for an element at the back and returns its slot.
+func (d *BlockDeque[T]) pushBack() *T {
+	if d.off+d.n == d.blocks.len()<<blockShift {
+		d.blocks.PushBack(d.newBlock())
+	}
+	d.n++
+	return d.ptr(d.n - 1)
+}
+
+// pushFront makes room for an element at the front and returns its slot.
+func (d *BlockDeque[T]) pushFront() *T {
+	if d.off == 0 {
+		d.blocks.PushFront(d.newBlock())
+		d.off = blockLen
+	}
+	d.off--
+	d.n++
+	return d.ptr(0)
+}
+
+// dropFront removes the n first elements and frees the emptied blocks. n must
+// be at most d.n.
+func (d *BlockDeque[T]) dropFront(n uint) {
+	d.off += n
+	d.n -= n
+	if d.n == 0 {
+		// Keep the offset small, so the remaining block can be reused.
+		d.off = 0
+		d.trimBack()
+		return
+	}
+	for d.off >= blockLen {
+		d.freeBlock(d.blocks.PopFrontZeroUnsafe())
+		d.off -= blockLen
+	}
+}
+
+// trimBack frees the blocks past the back.
+func (d *BlockDeque[T]) trimBack() {
+	used := blocksFor(d.off + d.n)
+	for d.blocks.len() > used {
+		d.freeBlock(d.blocks.PopBackZeroUnsafe())
+	}
+}
+
+// insert puts ts at index i, in order, moving the elements between i and the
+// nearer end. If the BlockDeque has a maximum length, the overflow is evicted
+// from the front, and insert returns how many elements were evicted. i must
+// be within [0, d.Len()].
+func (d *BlockDeque[T]) insert(i uint, ts []T) (evicted uint) {
+	k := uint(len(ts))
+	if k == 0 {
+		return 0
+	}
+	if n := d.n; i < n-i {
+		// Open k slots at the front and shift the elements before i into them.
+		for range k {
+			d.pushFront()
+		}
+		d.move(0, k, i)
+	} else {
+		// Open k slots at the back and shift the elements from i into them.
+		for range k {
+			d.pushBack()
+		}
+		d.move(i+k, i, n-i)
+	}
+	for x, t := range ts {
+		*d.ptr(i + uint(x)) = t
+	}
+	d.mod++
+
+	for d.maxLen != 0 && d.n > d.maxLen {
+		d.evict(d.PopFrontZeroUnsafe())
+		evicted++
+	}
+	return evicted
+}
+
+// delete removes the elements in [i, j), moving the elements between them and
+// the nearer end, zeroing the vacated slots, and freeing emptied blocks.
+// Indexes must be within bounds.
+func (d *BlockDeque[T]) delete(i, j uint) {
+	k := j - i
+	if k == 0 {
+		return
+	}
+	if i < d.n-j {
+		d.move(k, 0, i)
+		d.zero(0, k)
+		d.dropFront(k)
+	} else {
+		d.move(i, j, d.n-j)
+		d.zero(d.n-k, d.n)
+		d.n -= k
+		d.trimBack()
+	}
+	d.mod++
+}
+
+// move copies the n elements from index src to index dst a block at a time.
+// Like the copy built-in, it handles overlapping ranges. Both ranges must be
+// within bounds.
+func (d *BlockDeque[T]) move(dst, src, n uint) {
+	switch {
+	case dst < src:
+		for x := uint(0); x < n; {
+			s := d.span(src + x)
+			x += uint(copy(d.span(dst+x), s[:min(uint(len(s)), n-x)]))
+		}
+	case dst > src:
+		for x := n; x > 0; {
+			a, b := d.rspan(src+x), d.rspan(dst+x)
+			c := min(uint(len(a)), uint(len(b)), x)
+			copy(b[uint(len(b))-c:], a[uint(len(a))-c:])
+			x -= c
+		}
+	}
+}
+
+// zero zeroes the elements in [i, j).
+func (d *BlockDeque[T]) zero(i, j uint) {
+	var zero T
+	for ; i < j; i++ {
+		*d.ptr(i) = zero
+	}
+}
+
+func (d *BlockDeque[T]) newBlock() *[blockLen]T {
+	if len(d.free) == 0 {
+		return new([blockLen]T)
+	}
+	b := d.free[len(d.free)-1]
+	d.free[len(d.free)-1] = nil
+	d.free = d.free[:len(d.free)-1]
+	return b
+}
+
+func (d *BlockDeque[T]) freeBlock(b *[blockLen]T) {
+	d.free = append(d.free, b)
+}
+
+// shrinkFree releases free blocks once the BlockDeque is at <= 25% capacity,
+// until it is at <= 50% capacity.
+func (d *BlockDeque[T]) shrinkFree() {
+	if d.n <= d.cap()>>2 {
+		_ = d.resize(max(blocksFor(d.n<<1), d.blocks.len()))
+	}
+}
+
+func (d *BlockDeque[T]) checkMod(mod uint) {
+	if d.mod != mod {
+		panic("deque: BlockDeque modified during iteration")
+	}
+}
+
+func (d *BlockDeque[T]) checkBounds(i int) {
+	if i < 0 || i >= d.Len() {
+		panic(fmt.Sprintf("deque: index %d out of bounds with length %d", i, d.Len()))
+	}
+}
+
+func (d *BlockDeque[T]) checkRange(i, j int) {
+	if i < 0 || j < i || j > d.Len() {
+		panic(fmt.Sprintf("deque: range [%d:%d] out of bounds with length %d", i, j, d.Len()))
+	}
+}
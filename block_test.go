@@ -0,0 +1,257 @@
+package deque_test
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// pair sorts by key only, so that stable sorts can be told apart.
+type pair struct{ Key, Seq int }
+
+func cmpKey(a, b pair) int { return cmp.Compare(a.Key, b.Key) }
+
+// TestBlockDequeEdits applies random edits to a BlockDeque spanning several
+// blocks and to a Deque, and checks that they stay equal and that the
+// BlockDeque keeps only the blocks it needs.
+func TestBlockDequeEdits(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	seq := 0
+	next := func() pair {
+		seq++
+		return pair{r.IntN(50), seq}
+	}
+	b := deque.MakeBlockDeque[pair]()
+	d := deque.MakeDeque[pair]()
+	for range 300 {
+		p := next()
+		b.PushFront(p)
+		d.PushFront(p)
+	}
+
+	for step := range 2000 {
+		n := d.Len()
+		i := r.IntN(n + 1)
+		j := i + r.IntN(n-i+1)
+		var ts []pair
+		for range r.IntN(300) {
+			ts = append(ts, next())
+		}
+		switch op := r.IntN(12); op {
+		case 0:
+			b.Insert(i, ts...)
+			d.Insert(i, ts...)
+		case 1:
+			b.Delete(i, j)
+			d.Delete(i, j)
+		case 2:
+			b.Replace(i, j, ts...)
+			d.Replace(i, j, ts...)
+		case 3:
+			if n > 0 {
+				i = min(i, n-1)
+				if got, want := b.RemoveAt(i), d.RemoveAt(i); got != want {
+					t.Fatalf("step %d: RemoveAt(%d) = %v, want %v", step, i, got, want)
+				}
+			}
+		case 4:
+			b.RotateLeft(i - n/2)
+			d.RotateLeft(i - n/2)
+		case 5:
+			b.RotateRight(j)
+			d.RotateRight(j)
+		case 6:
+			b.Reverse()
+			d.Reverse()
+		case 7:
+			k := r.IntN(50)
+			f := func(p pair) bool { return p.Key < k }
+			if got, want := b.DeleteFunc(f), d.DeleteFunc(f); got != want {
+				t.Fatalf("step %d: DeleteFunc() = %d, want %d", step, got, want)
+			}
+		case 8:
+			eq := func(a, b pair) bool { return a.Key/8 == b.Key/8 }
+			if got, want := b.CompactFunc(eq), d.CompactFunc(eq); got != want {
+				t.Fatalf("step %d: CompactFunc() = %d, want %d", step, got, want)
+			}
+		case 9:
+			b.SortStableFunc(cmpKey)
+			d.SortStableFunc(cmpKey)
+			if !b.IsSortedFunc(cmpKey) {
+				t.Fatalf("step %d: IsSortedFunc() = false after SortStableFunc", step)
+			}
+			for _, p := range ts {
+				if got, want := b.InsertSortedFunc(p, cmpKey), d.InsertSortedFunc(p, cmpKey); got != want {
+					t.Fatalf("step %d: InsertSortedFunc(%v) = %d, want %d", step, p, got, want)
+				}
+			}
+			k := r.IntN(50)
+			pred := func(p pair) bool { return p.Key < k }
+			if got, want := b.PartitionPoint(pred), d.PartitionPoint(pred); got != want {
+				t.Fatalf("step %d: PartitionPoint() = %d, want %d", step, got, want)
+			}
+		case 10:
+			// Unstable sorts may order equal keys differently, so compare
+			// the keys and restore the same order.
+			b.SortFunc(cmpKey)
+			if !b.IsSortedFunc(cmpKey) {
+				t.Fatalf("step %d: IsSortedFunc() = false after SortFunc", step)
+			}
+			less := func(a, b pair) int { return cmp.Or(cmpKey(a, b), cmp.Compare(a.Seq, b.Seq)) }
+			b.SortFunc(less)
+			d.SortFunc(less)
+		case 11:
+			if got, want := b.IsSortedFunc(cmpKey), d.IsSortedFunc(cmpKey); got != want {
+				t.Fatalf("step %d: IsSortedFunc() = %t, want %t", step, got, want)
+			}
+		}
+		if got, want := b.MakeSliceCopy(), d.MakeSliceCopy(); !slices.Equal(got, want) {
+			t.Fatalf("step %d: BlockDeque holds %v, want %v", step, got, want)
+		}
+		if !deque.BlocksOK(b) {
+			t.Fatalf("step %d: BlockDeque holds blocks past its elements", step)
+		}
+	}
+}
+
+func TestBlockDequeCursor(t *testing.T) {
+	b := deque.MakeBlockDeque[int]()
+	for i := range 400 {
+		b.PushBack(i)
+	}
+	for it := b.Iterator(); it.Next(); {
+		if it.Value()%3 != 0 {
+			it.Remove()
+		}
+	}
+	c := b.CursorAt(50)
+	if c.Value() != 150 {
+		t.Fatalf("CursorAt(50).Value() = %d, want 150", c.Value())
+	}
+	c.InsertBefore(-1, -2)
+	c.InsertAfter(-3)
+	if c.Remove() != 150 || c.Value() != -3 || c.Index() != 52 {
+		t.Fatalf("Cursor points to %d at %d after Remove, want -3 at 52", c.Value(), c.Index())
+	}
+	want := []int{147, -1, -2, -3, 153}
+	if got := b.MakeSliceIndexCopy(49, 54); !slices.Equal(got, want) {
+		t.Fatalf("BlockDeque holds %v around the Cursor, want %v", got, want)
+	}
+
+	var back []int
+	for c := b.Back(); c.Valid(); c.Prev() {
+		back = append(back, c.Value())
+	}
+	if len(back) != b.Len() || back[0] != 399 || back[len(back)-1] != 0 {
+		t.Fatalf("Back() walked over %d elements from %d to %d", len(back), back[0], back[len(back)-1])
+	}
+
+	it := b.Iterator()
+	it.Next()
+	b.PushBack(1)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Iterator did not panic after PushBack")
+		}
+	}()
+	it.Value()
+}
+
+func TestBlockDequeViewAndBlocks(t *testing.T) {
+	b := deque.MakeBlockDeque[int]()
+	for i := range 500 {
+		b.PushFront(499 - i)
+	}
+	var parts int
+	var all []int
+	for s := range b.Blocks() {
+		parts++
+		all = append(all, s...)
+	}
+	if parts < 4 || !slices.Equal(all, b.MakeSliceCopy()) {
+		t.Fatalf("Blocks() yielded %d slices holding %d elements", parts, len(all))
+	}
+	if got := b.MakeContiguous(); !slices.Equal(got, all) {
+		t.Fatalf("MakeContiguous() = %v, want %v", got, all)
+	}
+
+	v := b.View(100, 400)
+	if v.Len() != 300 || v.At(0) != 100 || v.At(299) != 399 {
+		t.Fatalf("View(100, 400) has length %d from %d to %d", v.Len(), v.At(0), v.At(299))
+	}
+	var got []int
+	for s := range v.Blocks() {
+		got = append(got, s...)
+	}
+	if !slices.Equal(got, slices.Collect(v.Iter())) || !slices.Equal(got, b.MakeSliceIndexCopy(100, 400)) {
+		t.Fatal("View's Blocks and Iter don't match the range")
+	}
+
+	s := b.MakeSliceIndexCopyWithCapacity(498, 500, 8)
+	if len(s) != 8 || s[0] != 498 || s[1] != 499 || s[2] != 0 {
+		t.Fatalf("MakeSliceIndexCopyWithCapacity() = %v", s)
+	}
+}
+
+func TestBlockDequeRing(t *testing.T) {
+	b := deque.MakeBlockDeque[int]()
+	for i := range 300 {
+		b.PushBack(i)
+	}
+	var evicted []int
+	b.OnEvict(func(v int) { evicted = append(evicted, v) })
+	if err := b.SetMaxLen(200); err != nil || b.MaxLen() != 200 {
+		t.Fatalf("SetMaxLen(200) = %v with MaxLen() %d", err, b.MaxLen())
+	}
+	if b.Len() != 200 || b.PeekFrontUnsafe() != 100 || len(evicted) != 100 {
+		t.Fatalf("SetMaxLen(200) left %d elements from %d and evicted %d", b.Len(), b.PeekFrontUnsafe(), len(evicted))
+	}
+
+	evicted = nil
+	b.PushBack(300, 301)
+	b.PushFront(99)
+	*b.PushBackSlot() = 302
+	b.Insert(1, -1)
+	if want := []int{100, 101, 301, 99, 102}; !slices.Equal(evicted, want) {
+		t.Fatalf("evicted %v, want %v", evicted, want)
+	}
+	if b.Len() != 200 || b.At(0) != -1 || b.PeekBackUnsafe() != 302 {
+		t.Fatalf("BlockDeque holds %d elements from %d to %d", b.Len(), b.At(0), b.PeekBackUnsafe())
+	}
+	if got := b.InsertSortedFunc(-5, cmp.Compare[int]); got != -1 {
+		t.Fatalf("InsertSortedFunc() of an evicted element = %d, want -1", got)
+	}
+}
+
+func TestBlockDequeEqualFuncAndFull(t *testing.T) {
+	eq := func(a, b int) bool { return a == b }
+	b1, b2 := deque.MakeBlockDeque[int](), deque.MakeBlockDeque[int]()
+	for i := range 300 {
+		b1.PushBack(i)
+		b2.PushFront(299 - i)
+	}
+	// The elements start at different offsets in their blocks.
+	b2.PushFront(-1)
+	b2.DropFront(1)
+	if !b1.EqualFunc(b2, eq) {
+		t.Fatal("EqualFunc() = false for equal BlockDeques")
+	}
+	b2.Set(200, -1)
+	if b1.EqualFunc(b2, eq) || b1.EqualFunc(nil, eq) {
+		t.Fatal("EqualFunc() = true for different BlockDeques")
+	}
+
+	b, _ := deque.MakeBlockDequeWithCapacity[int](128)
+	for i := range 128 {
+		if b.Full() {
+			t.Fatalf("Full() = true with %d elements", i)
+		}
+		b.PushBack(i)
+	}
+	if !b.Full() {
+		t.Fatal("Full() = false with every slot used")
+	}
+}
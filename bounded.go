@@ -0,0 +1,251 @@
+package deque
+
+import (
+	"context"
+	"sync"
+)
+
+// BoundedDeque is a Deque with a fixed maximum length, safe for concurrent use
+// by multiple goroutines. Instead of reallocating when it's full, pushing
+// blocks until a consumer makes room, which provides backpressure between
+// producers and consumers. Its underlying buffer is allocated once by the
+// constructor and never reallocated.
+//
+// To create a BoundedDeque instance, you must use MakeBoundedDeque(maxLen).
+//
+// Closing a BoundedDeque follows the same rules as closing a SyncDeque.
+// PushBack and PushFront panic, while TryPush* and Push*Wait return ErrClosed.
+// Consumers can keep popping the remaining elements, and waiting pops return
+// ErrClosed only once the BoundedDeque is both closed and empty.
+type BoundedDeque[T any] struct {
+	mu       sync.Mutex
+	d        *Deque[T]
+	limit    uint
+	notEmpty notifier
+	notFull  notifier
+	closed   bool
+}
+
+// MakeBoundedDeque takes in the maximum number of elements the BoundedDeque
+// may hold. Unlike the capacity of a Deque, maxLen is not rounded up. Returns
+// an error if maxLen is not positive.
+func MakeBoundedDeque[T any](maxLen int) (*BoundedDeque[T], error) {
+	if maxLen <= 0 {
+		return nil, ErrInvalidMaxLen
+	}
+	d, _ := MakeDequeWithCapacity[T](maxLen)
+	return &BoundedDeque[T]{d: d, limit: uint(maxLen)}, nil
+}
+
+// Len returns the number of elements in the BoundedDeque. By the time the
+// caller inspects the result, other goroutines may have changed it.
+func (b *BoundedDeque[T]) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.d.Len()
+}
+
+// Cap returns the maximum number of elements the BoundedDeque may hold.
+func (b *BoundedDeque[T]) Cap() int { return int(b.limit) }
+
+// Full returns whether the BoundedDeque is full. Pushing to a full
+// BoundedDeque blocks or fails with ErrFull.
+func (b *BoundedDeque[T]) Full() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.d.len() == b.limit
+}
+
+// PushBack puts the elements at the back of the BoundedDeque, blocking while
+// it's full. Elements are pushed one at a time, so pushes from other
+// producers may be interleaved with them. Panics if the BoundedDeque is
+// closed.
+func (b *BoundedDeque[T]) PushBack(ts ...T) {
+	for _, t := range ts {
+		if b.pushWait(context.Background(), t, (*Deque[T]).PushBack) != nil {
+			panic("deque: push to closed BoundedDeque")
+		}
+	}
+}
+
+// PushFront puts the elements at the front of the BoundedDeque, blocking while
+// it's full. Elements are pushed one at a time, so pushes from other
+// producers may be interleaved with them. Panics if the BoundedDeque is
+// closed.
+func (b *BoundedDeque[T]) PushFront(ts ...T) {
+	for _, t := range ts {
+		if b.pushWait(context.Background(), t, (*Deque[T]).PushFront) != nil {
+			panic("deque: push to closed BoundedDeque")
+		}
+	}
+}
+
+// TryPushBack puts the elements at the back of the BoundedDeque without
+// blocking. Either every element is pushed or none is: it returns ErrFull if
+// they don't all fit, or ErrClosed if the BoundedDeque is closed.
+func (b *BoundedDeque[T]) TryPushBack(ts ...T) error {
+	return b.tryPush(ts, (*Deque[T]).PushBack)
+}
+
+// TryPushFront puts the elements at the front of the BoundedDeque without
+// blocking. Either every element is pushed or none is: it returns ErrFull if
+// they don't all fit, or ErrClosed if the BoundedDeque is closed.
+func (b *BoundedDeque[T]) TryPushFront(ts ...T) error {
+	return b.tryPush(ts, (*Deque[T]).PushFront)
+}
+
+// PushBackWait puts t at the back of the BoundedDeque, blocking while it's
+// full. It returns ctx.Err() if the context is done first, so use
+// context.WithDeadline or context.WithTimeout to bound the wait. It returns
+// ErrClosed if the BoundedDeque is closed.
+func (b *BoundedDeque[T]) PushBackWait(ctx context.Context, t T) error {
+	return b.pushWait(ctx, t, (*Deque[T]).PushBack)
+}
+
+// PushFrontWait puts t at the front of the BoundedDeque, blocking while it's
+// full. It returns ctx.Err() if the context is done first, so use
+// context.WithDeadline or context.WithTimeout to bound the wait. It returns
+// ErrClosed if the BoundedDeque is closed.
+func (b *BoundedDeque[T]) PushFrontWait(ctx context.Context, t T) error {
+	return b.pushWait(ctx, t, (*Deque[T]).PushFront)
+}
+
+// PeekBack returns the last element in the BoundedDeque. If it's empty, it
+// returns false.
+func (b *BoundedDeque[T]) PeekBack() (t T, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.d.PeekBack()
+}
+
+// PeekFront returns the first element in the BoundedDeque. If it's empty, it
+// returns false.
+func (b *BoundedDeque[T]) PeekFront() (t T, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.d.PeekFront()
+}
+
+// PopBack removes the last element in the BoundedDeque and returns it. If
+// it's empty, returns false without blocking. The slot is zeroed, so
+// references held by the element are released.
+func (b *BoundedDeque[T]) PopBack() (t T, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if t, ok = b.d.PopBackZero(); ok {
+		b.notFull.broadcast()
+	}
+	return
+}
+
+// PopFront removes the first element in the BoundedDeque and returns it. If
+// it's empty, returns false without blocking. The slot is zeroed, so
+// references held by the element are released.
+func (b *BoundedDeque[T]) PopFront() (t T, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if t, ok = b.d.PopFrontZero(); ok {
+		b.notFull.broadcast()
+	}
+	return
+}
+
+// PopBackWait removes the last element in the BoundedDeque and returns it,
+// blocking while it's empty. It returns ctx.Err() if the context is done
+// first, or ErrClosed if the BoundedDeque is closed and empty.
+func (b *BoundedDeque[T]) PopBackWait(ctx context.Context) (T, error) {
+	return b.popWait(ctx, (*Deque[T]).PopBackZero)
+}
+
+// PopFrontWait removes the first element in the BoundedDeque and returns it,
+// blocking while it's empty. It returns ctx.Err() if the context is done
+// first, or ErrClosed if the BoundedDeque is closed and empty.
+func (b *BoundedDeque[T]) PopFrontWait(ctx context.Context) (T, error) {
+	return b.popWait(ctx, (*Deque[T]).PopFrontZero)
+}
+
+// Close marks the BoundedDeque as closed and wakes up every waiting producer
+// and consumer. Elements already in the BoundedDeque can still be popped.
+// Calling Close more than once is a no-op.
+func (b *BoundedDeque[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.notEmpty.broadcast()
+	b.notFull.broadcast()
+}
+
+// Closed returns whether Close has been called.
+func (b *BoundedDeque[T]) Closed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+// Internal implementation for TryPushBack and TryPushFront.
+func (b *BoundedDeque[T]) tryPush(ts []T, push func(*Deque[T], ...T)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	if b.d.len()+uint(len(ts)) > b.limit {
+		return ErrFull
+	}
+	push(b.d, ts...)
+	b.notEmpty.broadcast()
+	return nil
+}
+
+// Internal implementation for the blocking pushes.
+func (b *BoundedDeque[T]) pushWait(ctx context.Context, t T, push func(*Deque[T], ...T)) error {
+	b.mu.Lock()
+	for {
+		if b.closed {
+			b.mu.Unlock()
+			return ErrClosed
+		}
+		if b.d.len() < b.limit {
+			push(b.d, t)
+			b.notEmpty.broadcast()
+			b.mu.Unlock()
+			return nil
+		}
+		notFull := b.notFull.wait()
+		b.mu.Unlock()
+
+		select {
+		case <-notFull:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		b.mu.Lock()
+	}
+}
+
+// Internal implementation for the blocking pops.
+func (b *BoundedDeque[T]) popWait(ctx context.Context, pop func(*Deque[T]) (T, bool)) (T, error) {
+	b.mu.Lock()
+	for {
+		if t, ok := pop(b.d); ok {
+			b.notFull.broadcast()
+			b.mu.Unlock()
+			return t, nil
+		}
+		if b.closed {
+			b.mu.Unlock()
+			var zero T
+			return zero, ErrClosed
+		}
+		notEmpty := b.notEmpty.wait()
+		b.mu.Unlock()
+
+		select {
+		case <-notEmpty:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+		b.mu.Lock()
+	}
+}
@@ -0,0 +1,119 @@
+package deque_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// TestBoundedDequeBackpressure checks that pushing to a full BoundedDeque
+// blocks until a consumer pops.
+func TestBoundedDequeBackpressure(t *testing.T) {
+	b, err := deque.MakeBoundedDeque[int](3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.PushBack(1, 2, 3)
+	if !b.Full() {
+		t.Fatal("Full() = false with 3 of 3 elements")
+	}
+
+	pushed := make(chan struct{})
+	go func() {
+		b.PushBack(4)
+		close(pushed)
+	}()
+	select {
+	case <-pushed:
+		t.Fatal("PushBack() on a full BoundedDeque did not block")
+	case <-time.After(blocked):
+	}
+	if v, ok := b.PopFront(); !ok || v != 1 {
+		t.Fatalf("PopFront() = %d, %t, want 1, true", v, ok)
+	}
+	<-pushed
+
+	for _, want := range []int{2, 3, 4} {
+		if v, err := b.PopFrontWait(context.Background()); err != nil || v != want {
+			t.Fatalf("PopFrontWait() = %d, %v, want %d, nil", v, err, want)
+		}
+	}
+}
+
+// TestBoundedDequeTryPush checks that TryPush* pushes every element or none.
+func TestBoundedDequeTryPush(t *testing.T) {
+	b, _ := deque.MakeBoundedDeque[int](3)
+	if err := b.TryPushBack(1, 2, 3, 4); !errors.Is(err, deque.ErrFull) {
+		t.Fatalf("TryPushBack() of 4 elements = %v, want %v", err, deque.ErrFull)
+	}
+	if b.Len() != 0 {
+		t.Fatalf("Len() = %d after a failed TryPushBack, want 0", b.Len())
+	}
+	if err := b.TryPushBack(2, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.TryPushFront(0, 1); !errors.Is(err, deque.ErrFull) {
+		t.Fatalf("TryPushFront() of 2 elements = %v, want %v", err, deque.ErrFull)
+	}
+	if err := b.TryPushFront(1); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []int{3, 2, 1} {
+		if v, ok := b.PopBack(); !ok || v != want {
+			t.Fatalf("PopBack() = %d, %t, want %d, true", v, ok, want)
+		}
+	}
+
+	b.Close()
+	if err := b.TryPushBack(1); !errors.Is(err, deque.ErrClosed) {
+		t.Fatalf("TryPushBack() after Close = %v, want %v", err, deque.ErrClosed)
+	}
+}
+
+func TestBoundedDequePushWaitDeadline(t *testing.T) {
+	b, _ := deque.MakeBoundedDeque[int](1)
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(blocked))
+	defer cancel()
+	if err := b.PushBackWait(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.PushFrontWait(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("PushFrontWait() on a full BoundedDeque = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if b.Len() != 1 {
+		t.Fatalf("Len() = %d after a timed out push, want 1", b.Len())
+	}
+
+	// Close wakes up blocked producers.
+	res := make(chan error)
+	go func() { res <- b.PushBackWait(context.Background(), 3) }()
+	time.Sleep(blocked)
+	b.Close()
+	if err := <-res; !errors.Is(err, deque.ErrClosed) {
+		t.Fatalf("PushBackWait() after Close = %v, want %v", err, deque.ErrClosed)
+	}
+}
+
+// TestBoundedDequeNoRealloc checks that the buffer allocated by the
+// constructor is never reallocated, however elements are pushed and popped.
+func TestBoundedDequeNoRealloc(t *testing.T) {
+	b, _ := deque.MakeBoundedDeque[int](5)
+	want := deque.BufCap(b)
+	for i := range 100 {
+		_ = b.TryPushBack(i, i)
+		_ = b.TryPushFront(i)
+		if i%3 == 0 {
+			b.PopFront()
+			b.PopBack()
+		}
+		if got := deque.BufCap(b); got != want {
+			t.Fatalf("buffer capacity = %d after %d pushes, want %d", got, i, want)
+		}
+	}
+	if b.Cap() != 5 {
+		t.Fatalf("Cap() = %d, want 5", b.Cap())
+	}
+}
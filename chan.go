@@ -0,0 +1,136 @@
+package deque
+
+import "sync/atomic"
+
+// UnboundedChan is a channel with an unbounded buffer. Values sent to In are
+// buffered in a Deque by a dedicated goroutine until they are received from
+// Out, in FIFO order, so sending to In never blocks for long.
+//
+// To create an UnboundedChan instance, you must use one of the available
+// constructors, NewUnboundedChan() or NewUnboundedChanWithLimit(limit, policy).
+//
+// Closing In closes Out once every buffered value has been received. Until
+// then, the goroutine keeps running, so In must eventually be closed or Out
+// drained to avoid leaking it. When the load drops, the buffer shrinks to give
+// memory back.
+type UnboundedChan[T any] struct {
+	// In is the sending side. Close it when done sending.
+	In chan<- T
+	// Out is the receiving side. It is closed after In is closed and every
+	// buffered value has been received.
+	Out <-chan T
+
+	buf     *Deque[T]
+	limit   uint
+	policy  DropPolicy
+	len     atomic.Int64
+	dropped atomic.Uint64
+}
+
+// DropPolicy decides which value an UnboundedChan with a limit drops when a
+// value is sent while its buffer is full.
+type DropPolicy int
+
+const (
+	// DropNewest discards the value being sent.
+	DropNewest DropPolicy = iota
+	// DropOldest discards the oldest buffered value to make room.
+	DropOldest
+)
+
+// NewUnboundedChan creates an UnboundedChan with no limit and starts its
+// goroutine.
+func NewUnboundedChan[T any]() *UnboundedChan[T] {
+	c, _ := NewUnboundedChanWithLimit[T](0, DropNewest)
+	return c
+}
+
+// NewUnboundedChanWithLimit creates an UnboundedChan that buffers at most limit
+// values and starts its goroutine. This is a soft cap: once it's reached, the
+// policy decides which value is dropped, and sending still doesn't block.
+// A limit of 0 means no limit. Returns an error if limit is negative.
+func NewUnboundedChanWithLimit[T any](limit int, policy DropPolicy) (*UnboundedChan[T], error) {
+	if limit < 0 {
+		return nil, ErrInvalidMaxLen
+	}
+	in := make(chan T)
+	out := make(chan T)
+	c := &UnboundedChan[T]{
+		In:     in,
+		Out:    out,
+		buf:    MakeDeque[T](),
+		limit:  uint(limit),
+		policy: policy,
+	}
+	if limit != 0 && policy == DropOldest {
+		_ = c.buf.SetMaxLen(limit)
+		c.buf.OnEvict(func(T) { c.dropped.Add(1) })
+	}
+	go c.run(in, out)
+	return c, nil
+}
+
+// Len returns the number of buffered values, not counting values the
+// goroutine is about to receive or deliver. By the time the caller inspects
+// the result, it may have changed.
+func (c *UnboundedChan[T]) Len() int { return int(c.len.Load()) }
+
+// Dropped returns how many values were dropped because the limit was reached.
+func (c *UnboundedChan[T]) Dropped() uint64 { return c.dropped.Load() }
+
+func (c *UnboundedChan[T]) run(in <-chan T, out chan<- T) {
+	defer close(out)
+	for {
+		if c.buf.Empty() {
+			t, ok := <-in
+			if !ok {
+				return
+			}
+			c.push(t)
+			continue
+		}
+
+		select {
+		case t, ok := <-in:
+			if !ok {
+				c.drain(out)
+				return
+			}
+			c.push(t)
+		case out <- c.buf.PeekFrontUnsafe():
+			c.pop()
+		}
+	}
+}
+
+// drain delivers every buffered value after In is closed.
+func (c *UnboundedChan[T]) drain(out chan<- T) {
+	for !c.buf.Empty() {
+		out <- c.buf.PeekFrontUnsafe()
+		c.pop()
+	}
+}
+
+func (c *UnboundedChan[T]) push(t T) {
+	// With DropOldest, the Deque's maximum length evicts on its own.
+	if c.limit != 0 && c.policy == DropNewest && c.buf.len() >= c.limit {
+		c.dropped.Add(1)
+		return
+	}
+	c.buf.PushBack(t)
+	c.len.Store(int64(c.buf.len()))
+}
+
+// pop removes the value that was just delivered, releasing its references and
+// shrinking the buffer once it's mostly empty.
+func (c *UnboundedChan[T]) pop() {
+	const minCapacity = 16
+	var zero T
+	c.buf.SetUnsafe(0, zero)
+	if c.buf.Cap() > minCapacity {
+		c.buf.PopFrontShrink()
+	} else {
+		c.buf.DropFront(1)
+	}
+	c.len.Store(int64(c.buf.len()))
+}
@@ -0,0 +1,115 @@
+package deque_test
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// TestUnboundedChanDrain checks that sending never waits for a receiver, and
+// that every buffered value is received in order after In is closed.
+func TestUnboundedChanDrain(t *testing.T) {
+	const items = 1000
+	c := deque.NewUnboundedChan[int]()
+	for i := range items {
+		c.In <- i
+	}
+	close(c.In)
+
+	next := 0
+	for v := range c.Out {
+		if v != next {
+			t.Fatalf("received %d, want %d", v, next)
+		}
+		next++
+	}
+	if next != items {
+		t.Fatalf("received %d values, want %d", next, items)
+	}
+	if c.Len() != 0 {
+		t.Fatalf("Len() = %d after draining, want 0", c.Len())
+	}
+}
+
+// TestUnboundedChanClose checks that Out stays open while In is open, even with
+// nothing buffered, and is closed once In is.
+func TestUnboundedChanClose(t *testing.T) {
+	c := deque.NewUnboundedChan[int]()
+	c.In <- 1
+	if v := <-c.Out; v != 1 {
+		t.Fatalf("received %d, want 1", v)
+	}
+	select {
+	case v, ok := <-c.Out:
+		t.Fatalf("received %d, %t from an empty UnboundedChan with In open", v, ok)
+	case <-time.After(blocked):
+	}
+
+	close(c.In)
+	select {
+	case v, ok := <-c.Out:
+		if ok {
+			t.Fatalf("received %d after closing In, want Out closed", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Out not closed after closing In")
+	}
+}
+
+func TestUnboundedChanLimit(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		policy deque.DropPolicy
+		want   []int
+	}{
+		{"DropNewest", deque.DropNewest, []int{0, 1, 2}},
+		{"DropOldest", deque.DropOldest, []int{7, 8, 9}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := deque.NewUnboundedChanWithLimit[int](3, tc.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range 10 {
+				c.In <- i
+			}
+			close(c.In)
+			var got []int
+			for v := range c.Out {
+				got = append(got, v)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("received %v, want %v", got, tc.want)
+			}
+			if c.Dropped() != 7 {
+				t.Fatalf("Dropped() = %d, want 7", c.Dropped())
+			}
+		})
+	}
+}
+
+// TestUnboundedChanNoLeak checks that the goroutine of every UnboundedChan
+// exits once In is closed and Out is drained.
+func TestUnboundedChanNoLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for range 10 {
+		c := deque.NewUnboundedChan[int]()
+		for i := range 100 {
+			c.In <- i
+		}
+		close(c.In)
+		for range c.Out {
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines running, want at most %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,103 @@
+package deque_test
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+)
+
+const wrapCap = 8
+
+// dequeAt returns a Deque with capacity wrapCap holding s, whose front is at
+// offset off of the underlying buffer, so that it wraps around whenever
+// off+len(s) > wrapCap.
+func dequeAt(t *testing.T, s []int, off int) *deque.Deque[int] {
+	t.Helper()
+	d, _ := deque.MakeDequeWithCapacity[int](wrapCap)
+	d.PushBack(make([]int, off)...)
+	d.DropFront(off)
+	d.PushBack(s...)
+	if d.Cap() != wrapCap {
+		t.Fatalf("Cap() = %d, want %d", d.Cap(), wrapCap)
+	}
+	return d
+}
+
+// variants returns s, s with each element changed, and s with an element
+// removed or added at each end, to exercise every outcome of a comparison.
+func variants(r *rand.Rand, s []int) [][]int {
+	vs := [][]int{slices.Clone(s)}
+	for i := range s {
+		v := slices.Clone(s)
+		v[i] += r.IntN(3) - 1
+		vs = append(vs, v)
+	}
+	if len(s) > 0 {
+		vs = append(vs, slices.Clone(s[1:]), slices.Clone(s[:len(s)-1]))
+	}
+	if len(s) < wrapCap {
+		vs = append(vs, append(slices.Clone(s), r.IntN(3)), append([]int{r.IntN(3)}, s...))
+	}
+	return vs
+}
+
+// TestCompareWrapOffsets compares Deques against their slice models for every
+// combination of lengths and wrap offsets of both Deques.
+func TestCompareWrapOffsets(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	eq := func(a, b int) bool { return a == b }
+	for n := range wrapCap + 1 {
+		s1 := make([]int, n)
+		for i := range s1 {
+			s1[i] = r.IntN(3)
+		}
+		for _, s2 := range variants(r, s1) {
+			for off1 := range wrapCap {
+				for off2 := range wrapCap {
+					d1, d2 := dequeAt(t, s1, off1), dequeAt(t, s2, off2)
+					if got, want := deque.Equal(d1, d2), slices.Equal(s1, s2); got != want {
+						t.Fatalf("Equal(%v@%d, %v@%d) = %t, want %t", s1, off1, s2, off2, got, want)
+					}
+					if got, want := d1.EqualFunc(d2, eq), slices.Equal(s1, s2); got != want {
+						t.Fatalf("EqualFunc(%v@%d, %v@%d) = %t, want %t", s1, off1, s2, off2, got, want)
+					}
+					if got, want := deque.Compare(d1, d2), slices.Compare(s1, s2); got != want {
+						t.Fatalf("Compare(%v@%d, %v@%d) = %d, want %d", s1, off1, s2, off2, got, want)
+					}
+					if got, want := deque.CompareFunc(d2, d1, cmp.Compare[int]), slices.Compare(s2, s1); got != want {
+						t.Fatalf("CompareFunc(%v@%d, %v@%d) = %d, want %d", s2, off2, s1, off1, got, want)
+					}
+					if got, want := deque.EqualSlice(d1, s2), slices.Equal(s1, s2); got != want {
+						t.Fatalf("EqualSlice(%v@%d, %v) = %t, want %t", s1, off1, s2, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCompareNil(t *testing.T) {
+	var nilDeque *deque.Deque[int]
+	empty := deque.MakeDeque[int]()
+	if !deque.Equal(nilDeque, nilDeque) || deque.Equal(nilDeque, empty) || deque.Equal(empty, nilDeque) {
+		t.Fatal("Equal must only consider nil equal to nil")
+	}
+	if deque.Compare(nilDeque, empty) != 0 || deque.Compare(empty, nilDeque) != 0 {
+		t.Fatal("Compare must consider nil equal to empty")
+	}
+	if !deque.EqualSlice(nilDeque, nil) || !deque.EqualSlice(empty, []int{}) {
+		t.Fatal("EqualSlice must consider nil and empty equal")
+	}
+}
+
+func TestCompareFuncTypes(t *testing.T) {
+	d1 := deque.CopySliceToDeque([]int{1, 2, 3})
+	d2 := deque.CopySliceToDeque([]string{"1", "2", "4"})
+	byLen := func(i int, s string) int { return cmp.Compare(i, len(s)) }
+	if got := deque.CompareFunc(d1, d2, byLen); got != +1 {
+		t.Fatalf("CompareFunc() = %d, want +1", got)
+	}
+}
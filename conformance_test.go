@@ -0,0 +1,29 @@
+package deque_test
+
+import (
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+	"github.com/lucasgdosr/deque/dequetest"
+)
+
+func TestDequeConformance(t *testing.T) {
+	dequetest.RunConformance(t, func() deque.Interface[int] {
+		return deque.MakeDeque[int]()
+	})
+}
+
+// TestDequeMinimalCapacityConformance starts from a single slot, so every
+// push in the suite crosses a reallocation or a wraparound.
+func TestDequeMinimalCapacityConformance(t *testing.T) {
+	dequetest.RunConformance(t, func() deque.Interface[int] {
+		d, _ := deque.MakeDequeWithCapacity[int](1)
+		return d
+	})
+}
+
+func TestBlockDequeConformance(t *testing.T) {
+	dequetest.RunConformance(t, func() deque.Interface[int] {
+		return deque.MakeBlockDeque[int]()
+	})
+}
@@ -0,0 +1,44 @@
+package deque_test
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestMakeContiguous calls MakeContiguous for every length and wrap offset,
+// which covers elements that are already contiguous, wrapped elements with
+// room to shift either part, and wrapped elements that need a rotation
+// because the buffer is full or nearly so. It checks the elements against
+// MakeSliceCopy, and that the returned slice aliases the buffer.
+func TestMakeContiguous(t *testing.T) {
+	for n := range wrapCap + 1 {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i + 1
+		}
+		for off := range wrapCap {
+			d := dequeAt(t, s, off)
+			want := d.MakeSliceCopy()
+			got := d.MakeContiguous()
+			if !slices.Equal(got, want) || !slices.Equal(got, s) {
+				t.Fatalf("length %d, offset %d: MakeContiguous() = %v, want %v", n, off, got, s)
+			}
+			if d.Cap() != wrapCap {
+				t.Fatalf("length %d, offset %d: Cap() = %d after MakeContiguous, want %d", n, off, d.Cap(), wrapCap)
+			}
+			if a, b := d.AsSlices(); len(a) != n || len(b) != 0 {
+				t.Fatalf("length %d, offset %d: AsSlices() = %v, %v after MakeContiguous", n, off, a, b)
+			}
+			if n == 0 {
+				continue
+			}
+			if &got[0] != d.PtrAt(0) || &got[n-1] != d.PtrAt(n-1) {
+				t.Fatalf("length %d, offset %d: MakeContiguous() does not alias the buffer", n, off)
+			}
+			got[0] = -1
+			if d.At(0) != -1 {
+				t.Fatalf("length %d, offset %d: writing through MakeContiguous() didn't change the Deque", n, off)
+			}
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package deque
+
+// Cursor is a bidirectional position in a Deque, like a C++ deque iterator. It
+// reads and writes the element it points to, and inserts or removes elements
+// around it, shifting whichever side of the ring is shorter. A Cursor stays
+// valid across its own mutations, but panics if the Deque is structurally
+// modified by anything else.
+//
+// Besides the elements, a Cursor may point one position before the front or
+// one position past the back, where Valid returns false. This is where Next
+// and Prev leave it after walking off either end.
+//
+// To create a Cursor, use d.CursorAt(i), d.Front(), or d.Back().
+type Cursor[T any] struct {
+	d   *Deque[T]
+	i   int
+	mod uint
+}
+
+// CursorAt returns a Cursor pointing to the i-th element. Panics if out of
+// bounds.
+func (d *Deque[T]) CursorAt(i int) *Cursor[T] {
+	d.checkBounds(i)
+	return &Cursor[T]{d: d, i: i, mod: d.mod}
+}
+
+// Front returns a Cursor pointing to the first element. If the Deque is empty,
+// the Cursor points past the back.
+func (d *Deque[T]) Front() *Cursor[T] {
+	return &Cursor[T]{d: d, mod: d.mod}
+}
+
+// Back returns a Cursor pointing to the last element. If the Deque is empty,
+// the Cursor points before the front.
+func (d *Deque[T]) Back() *Cursor[T] {
+	return &Cursor[T]{d: d, i: d.Len() - 1, mod: d.mod}
+}
+
+// Valid returns whether the Cursor points to an element.
+func (c *Cursor[T]) Valid() bool {
+	c.d.checkMod(c.mod)
+	return c.i >= 0 && c.i < c.d.Len()
+}
+
+// Index returns the index of the element the Cursor points to. It is -1 before
+// the front and d.Len() past the back.
+func (c *Cursor[T]) Index() int {
+	c.d.checkMod(c.mod)
+	return c.i
+}
+
+// Next moves the Cursor to the next element and returns whether there is one.
+// Past the back, it stays there.
+func (c *Cursor[T]) Next() bool {
+	c.d.checkMod(c.mod)
+	c.i = min(c.i+1, c.d.Len())
+	return c.Valid()
+}
+
+// Prev moves the Cursor to the previous element and returns whether there is
+// one. Before the front, it stays there.
+func (c *Cursor[T]) Prev() bool {
+	c.d.checkMod(c.mod)
+	c.i = max(c.i-1, -1)
+	return c.Valid()
+}
+
+// Value returns the element the Cursor points to. Panics if it's not Valid.
+func (c *Cursor[T]) Value() T {
+	c.checkValid()
+	return c.d.AtUnsafe(c.i)
+}
+
+// Set overwrites the element the Cursor points to. Panics if it's not Valid.
+func (c *Cursor[T]) Set(t T) {
+	c.checkValid()
+	c.d.SetUnsafe(c.i, t)
+}
+
+// InsertBefore inserts the elements before the one the Cursor points to, in
+// order, and keeps pointing to the same element. Past the back, it appends to
+// the Deque. Panics if the Cursor is before the front.
+func (c *Cursor[T]) InsertBefore(ts ...T) {
+	c.d.checkMod(c.mod)
+	if c.i < 0 {
+		panic("deque: InsertBefore with Cursor before the front")
+	}
+	evicted := c.d.insert(uint(c.i), ts)
+	c.i = max(c.i+len(ts)-int(evicted), -1)
+	c.mod = c.d.mod
+}
+
+// InsertAfter inserts the elements after the one the Cursor points to, in
+// order, and keeps pointing to the same element. Before the front, it prepends
+// to the Deque. Panics if the Cursor is past the back.
+func (c *Cursor[T]) InsertAfter(ts ...T) {
+	c.d.checkMod(c.mod)
+	if c.i >= c.d.Len() {
+		panic("deque: InsertAfter with Cursor past the back")
+	}
+	evicted := c.d.insert(uint(c.i+1), ts)
+	c.i = max(c.i-int(evicted), -1)
+	c.mod = c.d.mod
+}
+
+// Remove removes the element the Cursor points to and returns it, zeroing the
+// vacated slot. The Cursor then points to the element that followed it, or
+// past the back. Panics if it's not Valid.
+func (c *Cursor[T]) Remove() T {
+	c.checkValid()
+	t := c.d.AtUnsafe(c.i)
+	c.d.delete(uint(c.i), uint(c.i)+1)
+	c.mod = c.d.mod
+	return t
+}
+
+func (c *Cursor[T]) checkValid() {
+	if !c.Valid() {
+		panic("deque: Cursor does not point to an element")
+	}
+}
+
+// BlockCursor is a bidirectional position in a BlockDeque, like Cursor is for
+// a Deque. Inserting or removing elements around it moves only the elements
+// between the edit and the nearer end. A BlockCursor stays valid across its
+// own mutations, but panics if the BlockDeque is structurally modified by
+// anything else.
+//
+// To create a BlockCursor, use d.CursorAt(i), d.Front(), or d.Back() on a
+// BlockDeque.
+type BlockCursor[T any] struct {
+	d   *BlockDeque[T]
+	i   int
+	mod uint
+}
+
+// CursorAt returns a BlockCursor pointing to the i-th element. Panics if out
+// of bounds.
+func (d *BlockDeque[T]) CursorAt(i int) *BlockCursor[T] {
+	d.checkBounds(i)
+	return &BlockCursor[T]{d: d, i: i, mod: d.mod}
+}
+
+// Front returns a BlockCursor pointing to the first element. If the
+// BlockDeque is empty, the BlockCursor points past the back.
+func (d *BlockDeque[T]) Front() *BlockCursor[T] {
+	return &BlockCursor[T]{d: d, mod: d.mod}
+}
+
+// Back returns a BlockCursor pointing to the last element. If the BlockDeque
+// is empty, the BlockCursor points before the front.
+func (d *BlockDeque[T]) Back() *BlockCursor[T] {
+	return &BlockCursor[T]{d: d, i: d.Len() - 1, mod: d.mod}
+}
+
+// Valid returns whether the BlockCursor points to an element.
+func (c *BlockCursor[T]) Valid() bool {
+	c.d.checkMod(c.mod)
+	return c.i >= 0 && c.i < c.d.Len()
+}
+
+// Index returns the index of the element the BlockCursor points to. It is -1
+// before the front and d.Len() past the back.
+func (c *BlockCursor[T]) Index() int {
+	c.d.checkMod(c.mod)
+	return c.i
+}
+
+// Next moves the BlockCursor to the next element and returns whether there is
+// one. Past the back, it stays there.
+func (c *BlockCursor[T]) Next() bool {
+	c.d.checkMod(c.mod)
+	c.i = min(c.i+1, c.d.Len())
+	return c.Valid()
+}
+
+// Prev moves the BlockCursor to the previous element and returns whether
+// there is one. Before the front, it stays there.
+func (c *BlockCursor[T]) Prev() bool {
+	c.d.checkMod(c.mod)
+	c.i = max(c.i-1, -1)
+	return c.Valid()
+}
+
+// Value returns the element the BlockCursor points to. Panics if it's not
+// Valid.
+func (c *BlockCursor[T]) Value() T {
+	c.checkValid()
+	return c.d.AtUnsafe(c.i)
+}
+
+// Set overwrites the element the BlockCursor points to. Panics if it's not
+// Valid.
+func (c *BlockCursor[T]) Set(t T) {
+	c.checkValid()
+	c.d.SetUnsafe(c.i, t)
+}
+
+// InsertBefore inserts the elements before the one the BlockCursor points to,
+// in order, and keeps pointing to the same element. Past the back, it appends
+// to the BlockDeque. Panics if the BlockCursor is before the front.
+func (c *BlockCursor[T]) InsertBefore(ts ...T) {
+	c.d.checkMod(c.mod)
+	if c.i < 0 {
+		panic("deque: InsertBefore with BlockCursor before the front")
+	}
+	evicted := c.d.insert(uint(c.i), ts)
+	c.i = max(c.i+len(ts)-int(evicted), -1)
+	c.mod = c.d.mod
+}
+
+// InsertAfter inserts the elements after the one the BlockCursor points to, in
+// order, and keeps pointing to the same element. Before the front, it
+// prepends to the BlockDeque. Panics if the BlockCursor is past the back.
+func (c *BlockCursor[T]) InsertAfter(ts ...T) {
+	c.d.checkMod(c.mod)
+	if c.i >= c.d.Len() {
+		panic("deque: InsertAfter with BlockCursor past the back")
+	}
+	evicted := c.d.insert(uint(c.i+1), ts)
+	c.i = max(c.i-int(evicted), -1)
+	c.mod = c.d.mod
+}
+
+// Remove removes the element the BlockCursor points to and returns it, zeroing
+// the vacated slot. The BlockCursor then points to the element that followed
+// it, or past the back. Panics if it's not Valid.
+func (c *BlockCursor[T]) Remove() T {
+	c.checkValid()
+	t := c.d.AtUnsafe(c.i)
+	c.d.delete(uint(c.i), uint(c.i)+1)
+	c.mod = c.d.mod
+	return t
+}
+
+func (c *BlockCursor[T]) checkValid() {
+	if !c.Valid() {
+		panic("deque: BlockCursor does not point to an element")
+	}
+}
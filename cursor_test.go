@@ -0,0 +1,108 @@
+package deque_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// TestCursorEdits edits around a Cursor at the front, at the back, and on
+// either side of the wraparound, at every wrap offset, and checks the result
+// against slices.Insert and slices.Delete, and that the Cursor keeps pointing
+// where it should.
+func TestCursorEdits(t *testing.T) {
+	s := []int{0, 1, 2, 3, 4, 5}
+	for off := range wrapCap {
+		positions := []int{0, len(s) - 1}
+		if w := wrapCap - off; w < len(s) {
+			positions = append(positions, w-1, w)
+		}
+		for _, i := range positions {
+			d := dequeAt(t, s, off)
+			c := d.CursorAt(i)
+			c.InsertBefore(10, 11)
+			if want := slices.Insert(slices.Clone(s), i, 10, 11); !slices.Equal(d.MakeSliceCopy(), want) {
+				t.Fatalf("offset %d: InsertBefore at %d left %v, want %v", off, i, d.MakeSliceCopy(), want)
+			}
+			if c.Index() != i+2 || c.Value() != s[i] {
+				t.Fatalf("offset %d: InsertBefore at %d left the Cursor at %d on %d", off, i, c.Index(), c.Value())
+			}
+
+			d = dequeAt(t, s, off)
+			c = d.CursorAt(i)
+			c.InsertAfter(10, 11)
+			if want := slices.Insert(slices.Clone(s), i+1, 10, 11); !slices.Equal(d.MakeSliceCopy(), want) {
+				t.Fatalf("offset %d: InsertAfter at %d left %v, want %v", off, i, d.MakeSliceCopy(), want)
+			}
+			if c.Index() != i || c.Value() != s[i] {
+				t.Fatalf("offset %d: InsertAfter at %d left the Cursor at %d on %d", off, i, c.Index(), c.Value())
+			}
+
+			d = dequeAt(t, s, off)
+			c = d.CursorAt(i)
+			if v := c.Remove(); v != s[i] {
+				t.Fatalf("offset %d: Remove at %d = %d, want %d", off, i, v, s[i])
+			}
+			if want := slices.Delete(slices.Clone(s), i, i+1); !slices.Equal(d.MakeSliceCopy(), want) {
+				t.Fatalf("offset %d: Remove at %d left %v, want %v", off, i, d.MakeSliceCopy(), want)
+			}
+			if c.Index() != i || c.Valid() != (i < len(s)-1) || c.Valid() && c.Value() != s[i+1] {
+				t.Fatalf("offset %d: Remove at %d left the Cursor at %d", off, i, c.Index())
+			}
+		}
+	}
+}
+
+// TestCursorEnds walks a Cursor off both ends and edits from there.
+func TestCursorEnds(t *testing.T) {
+	d := deque.MakeDeque[int]()
+	if c := d.Front(); c.Valid() || c.Index() != 0 {
+		t.Fatalf("Front() of an empty Deque is at %d, want past the back at 0", c.Index())
+	}
+	c := d.Back()
+	if c.Valid() || c.Index() != -1 {
+		t.Fatalf("Back() of an empty Deque is at %d, want before the front at -1", c.Index())
+	}
+	c.InsertAfter(1, 2)
+	if c.Prev() || c.Index() != -1 || !slices.Equal(d.MakeSliceCopy(), []int{1, 2}) {
+		t.Fatalf("InsertAfter() before the front left %v with the Cursor at %d", d.MakeSliceCopy(), c.Index())
+	}
+	mustPanic(t, "InsertBefore before the front", func() { c.InsertBefore(0) })
+
+	for c.Next() {
+	}
+	if c.Index() != 2 || c.Next() || c.Index() != 2 {
+		t.Fatalf("Next() walked off the back to %d, want 2", c.Index())
+	}
+	c.InsertBefore(3)
+	if c.Index() != 3 || !slices.Equal(d.MakeSliceCopy(), []int{1, 2, 3}) {
+		t.Fatalf("InsertBefore() past the back left %v with the Cursor at %d", d.MakeSliceCopy(), c.Index())
+	}
+	mustPanic(t, "InsertAfter past the back", func() { c.InsertAfter(4) })
+	mustPanic(t, "Value past the back", func() { c.Value() })
+	mustPanic(t, "Remove past the back", func() { c.Remove() })
+}
+
+// TestCursorInvalidated checks that a Cursor panics once the Deque is
+// structurally modified other than through it, but not after overwrites.
+func TestCursorInvalidated(t *testing.T) {
+	d := dequeAt(t, []int{0, 1, 2, 3}, 6)
+	c := d.CursorAt(1)
+	d.Set(1, 10)
+	if c.Value() != 10 {
+		t.Fatalf("Value() = %d after Set, want 10", c.Value())
+	}
+	d.PushBack(4)
+	mustPanic(t, "Valid", func() { c.Valid() })
+	mustPanic(t, "Index", func() { c.Index() })
+	mustPanic(t, "Next", func() { c.Next() })
+	mustPanic(t, "Value", func() { c.Value() })
+	mustPanic(t, "InsertBefore", func() { c.InsertBefore(5) })
+	mustPanic(t, "Remove", func() { c.Remove() })
+
+	// Edits through another Cursor invalidate this one too.
+	c = d.Front()
+	d.Back().Remove()
+	mustPanic(t, "Value after another Cursor's Remove", func() { c.Value() })
+}
@@ -7,6 +7,7 @@ import (
 	"iter"
 	"math/bits"
 	"slices"
+	"sort"
 )
 
 // Deque is a double-ended queue that can be used for either LIFO or FIFO
@@ -23,9 +24,22 @@ import (
 // ever overflows its underlying buffer, it reallocates to twice the size. It
 // does not shrink by default, so you must explicitly call a method to shrink
 // it.
+//
+// A Deque may also have a maximum length, set with MakeRing(n) or SetMaxLen(n).
+// Pushing past the maximum length evicts elements from the opposite end
+// instead of growing the Deque.
+//
+// Iterators are fail-fast: they panic if the Deque is structurally modified
+// during iteration, that is, if elements are pushed, popped, dropped, or the
+// buffer is reallocated. Overwriting elements with Set or Swap is allowed. To
+// remove elements while iterating, use an Iterator.
 type Deque[T any] struct {
 	buf              []T
 	head, tail, mask uint
+	maxLen           uint
+	onEvict          func(T)
+	// mod counts structural modifications, so iterators can detect them.
+	mod uint
 }
 
 /*****************************************************************************
@@ -59,9 +73,22 @@ func MakeDequeWithCapacity[T any](capacity int) (*Deque[T], error) {
 func CopySliceToDeque[T any](s []T) *Deque[T] {
 	d, _ := MakeDequeWithCapacity[T](len(s))
 	copy(d.buf, s)
+	d.tail = uint(len(s))
 	return d
 }
 
+// MakeRing allocates a Deque holding at most n elements, like Python's
+// collections.deque with a maxlen. Pushing to a full ring evicts elements from
+// the opposite end. Returns an error if n is not positive.
+func MakeRing[T any](n int) (*Deque[T], error) {
+	if n <= 0 {
+		return nil, ErrInvalidMaxLen
+	}
+	d, _ := MakeDequeWithCapacity[T](n)
+	d.maxLen = uint(n)
+	return d, nil
+}
+
 /*****************************************************************************
  * DEQUE API
  *****************************************************************************/
@@ -78,7 +105,8 @@ func (d *Deque[T]) len() uint { return d.tail - d.head }
 // Empty returns whether the Deque is empty.
 func (d *Deque[T]) Empty() bool { return d.tail == d.head }
 
-// Full returns whether the Deque is full. Pushing to a full Deque reallocates.
+// Full returns whether the Deque is full. Pushing to a full Deque reallocates,
+// unless it's at its maximum length, in which case pushing evicts instead.
 func (d *Deque[T]) Full() bool { return d.len() == d.cap() }
 
 // PushBack takes in a variable number of arguments and puts them at the back
@@ -88,7 +116,14 @@ func (d *Deque[T]) Full() bool { return d.len() == d.cap() }
 // PushBack reallocates at most once, no matter how many arguments. It is more
 // efficient to push multiple elements at once. The last argument is the new
 // back of the list.
+//
+// If the Deque has a maximum length, PushBack evicts elements from the front
+// to make room instead of growing past it.
 func (d *Deque[T]) PushBack(ts ...T) {
+	if d.maxLen != 0 {
+		d.pushBackRing(ts)
+		return
+	}
 	n := uint(len(ts))
 	if d.len()+n > d.cap() {
 		d.resize(ceilPow2(d.len() + n))
@@ -97,6 +132,7 @@ func (d *Deque[T]) PushBack(ts ...T) {
 		d.buf[(d.tail+uint(i))&d.mask] = t
 	}
 	d.tail += n
+	d.mod++
 }
 
 // PushFront takes in a variable number of arguments and puts them at the front
@@ -105,7 +141,14 @@ func (d *Deque[T]) PushBack(ts ...T) {
 // PushFront reallocates at most once, no matter how many arguments. It is more
 // efficient to push multiple elements at once. The last argument is the new
 // front of the list.
+//
+// If the Deque has a maximum length, PushFront evicts elements from the back
+// to make room instead of growing past it.
 func (d *Deque[T]) PushFront(ts ...T) {
+	if d.maxLen != 0 {
+		d.pushFrontRing(ts)
+		return
+	}
 	n := uint(len(ts))
 	if d.len()+n > d.cap() {
 		d.resize(ceilPow2(d.len() + n))
@@ -115,6 +158,7 @@ func (d *Deque[T]) PushFront(ts ...T) {
 		d.buf[(base-uint(i))&d.mask] = t
 	}
 	d.head -= n
+	d.mod++
 }
 
 // PeekBack returns the last element in the Deque. If the Deque is empty, it
@@ -155,6 +199,7 @@ func (d *Deque[T]) PeekFrontUnsafe() T {
 func (d *Deque[T]) PopBack() (t T, ok bool) {
 	if t, ok = d.PeekBack(); ok {
 		d.tail--
+		d.mod++
 	}
 	return
 }
@@ -182,6 +227,7 @@ func (d *Deque[T]) PopBackZero() (t T, ok bool) {
 func (d *Deque[T]) PopBackShrink() (t T, ok bool) {
 	if t, ok = d.PeekBack(); ok {
 		d.tail--
+		d.mod++
 	}
 	if d.len() <= d.cap()>>2 {
 		d.resize(ceilPow2(d.len() << 1))
@@ -196,6 +242,7 @@ func (d *Deque[T]) PopBackShrink() (t T, ok bool) {
 func (d *Deque[T]) PopBackUnsafe() T {
 	result := d.PeekBackUnsafe()
 	d.tail--
+	d.mod++
 	return result
 }
 
@@ -219,6 +266,7 @@ func (d *Deque[T]) PopBackZeroUnsafe() T {
 func (d *Deque[T]) PopFront() (t T, ok bool) {
 	if t, ok = d.PeekFront(); ok {
 		d.head++
+		d.mod++
 	}
 	return
 }
@@ -246,6 +294,7 @@ func (d *Deque[T]) PopFrontZero() (t T, ok bool) {
 func (d *Deque[T]) PopFrontShrink() (t T, ok bool) {
 	if t, ok = d.PeekFront(); ok {
 		d.head++
+		d.mod++
 	}
 	if d.len() <= d.cap()>>2 {
 		d.resize(ceilPow2(d.len() << 1))
@@ -261,6 +310,7 @@ func (d *Deque[T]) PopFrontShrink() (t T, ok bool) {
 func (d *Deque[T]) PopFrontUnsafe() T {
 	result := d.PeekFrontUnsafe()
 	d.head++
+	d.mod++
 	return result
 }
 
@@ -283,6 +333,7 @@ func (d *Deque[T]) PopFrontZeroUnsafe() T {
 func (d *Deque[T]) DropFront(n int) {
 	if n >= 0 {
 		d.head += min(uint(n), d.len())
+		d.mod++
 	}
 }
 
@@ -299,6 +350,7 @@ func (d *Deque[T]) DropFrontZero(n int) {
 			d.buf[i&d.mask] = zero
 		}
 		d.head += n
+		d.mod++
 	}
 }
 
@@ -309,6 +361,7 @@ func (d *Deque[T]) DropFrontZero(n int) {
 func (d *Deque[T]) DropBack(n int) {
 	if n >= 0 {
 		d.tail -= min(uint(n), d.len())
+		d.mod++
 	}
 }
 
@@ -324,6 +377,70 @@ func (d *Deque[T]) DropBackZero(n int) {
 			d.buf[i&d.mask] = zero
 		}
 		d.tail -= n
+		d.mod++
+	}
+}
+
+/*****************************************************************************
+ * RING API
+ *****************************************************************************/
+
+// MaxLen returns the maximum length of the Deque, or 0 if it has none.
+func (d *Deque[T]) MaxLen() int { return int(d.maxLen) }
+
+// SetMaxLen sets the maximum length of the Deque. If the Deque holds more than
+// n elements, the excess is evicted from the front. Passing 0 removes the
+// limit. Returns an error if n is negative.
+func (d *Deque[T]) SetMaxLen(n int) error {
+	if n < 0 {
+		return ErrInvalidMaxLen
+	}
+	d.maxLen = uint(n)
+	for n != 0 && d.len() > d.maxLen {
+		d.evict(d.PopFrontZeroUnsafe())
+	}
+	return nil
+}
+
+// OnEvict registers a function to be called with every element evicted by a
+// push past the maximum length, in eviction order. This is useful to archive
+// or release evicted elements. Passing nil unregisters it. f must not modify
+// the Deque.
+func (d *Deque[T]) OnEvict(f func(T)) { d.onEvict = f }
+
+// Internal implementation for PushBack with a maximum length.
+func (d *Deque[T]) pushBackRing(ts []T) {
+	if need := min(d.len()+uint(len(ts)), d.maxLen); need > d.cap() {
+		d.resize(ceilPow2(need))
+	}
+	for _, t := range ts {
+		if d.len() >= d.maxLen {
+			d.evict(d.PopFrontZeroUnsafe())
+		}
+		d.buf[d.tail&d.mask] = t
+		d.tail++
+	}
+	d.mod++
+}
+
+// Internal implementation for PushFront with a maximum length.
+func (d *Deque[T]) pushFrontRing(ts []T) {
+	if need := min(d.len()+uint(len(ts)), d.maxLen); need > d.cap() {
+		d.resize(ceilPow2(need))
+	}
+	for _, t := range ts {
+		if d.len() >= d.maxLen {
+			d.evict(d.PopBackZeroUnsafe())
+		}
+		d.head--
+		d.buf[d.head&d.mask] = t
+	}
+	d.mod++
+}
+
+func (d *Deque[T]) evict(t T) {
+	if d.onEvict != nil {
+		d.onEvict(t)
 	}
 }
 
@@ -367,6 +484,7 @@ func (d *Deque[T]) resize(newCap uint) error {
 	d.head = 0
 	d.tail = oldLen
 	d.mask = newCap - 1
+	d.mod++
 	return nil
 }
 
@@ -389,19 +507,37 @@ func (d *Deque[T]) Shrink() uint {
 	return newCap
 }
 
+// AsSlices returns the two contiguous parts of the Deque without copying. The
+// elements in order are those of a followed by those of b, and b is empty
+// unless the elements wrap around the end of the underlying buffer. The
+// slices share memory with the Deque, so writes through them are visible in
+// the Deque. They are only valid until the Deque is structurally modified.
+//
+// This is useful to pass the elements to functions that take slices, such as
+// io.Writer.Write or slices.Max, without allocating.
+func (d *Deque[T]) AsSlices() (a, b []T) { return d.slices() }
+
 // Helper to reuse the slices package functions.
 func (d *Deque[T]) slices() (a, b []T) {
 	if d == nil || d.Empty() {
 		return nil, nil
 	}
+	return d.span(d.head, d.tail)
+}
 
-	h := d.head & d.mask
-	t := d.tail & d.mask
+// span returns the two contiguous parts of the buffer between the unmasked
+// counters start and end.
+func (d *Deque[T]) span(start, end uint) (a, b []T) {
+	n := end - start
+	if n == 0 {
+		return nil, nil
+	}
 
-	if h < t {
-		return d.buf[h:t], nil
+	h := start & d.mask
+	if h+n <= d.cap() {
+		return d.buf[h : h+n], nil
 	}
-	return d.buf[h:], d.buf[:t]
+	return d.buf[h:], d.buf[:h+n-d.cap()]
 }
 
 // MakeSliceCopy allocates a slice to hold every Deque element and copies them.
@@ -483,6 +619,59 @@ func (d *Deque[T]) SetUnsafe(i int, t T) {
 	d.buf[(d.head+uint(i))&d.mask] = t
 }
 
+// PtrAt returns a pointer to the i-th element in the Deque, so that large
+// elements can be read and updated without copying. Panics if out of bounds.
+//
+// The pointer addresses a slot of the underlying buffer, not an element. It
+// dangles, pointing to the old buffer, after any reallocation: a push or
+// insert that grows the Deque, Reserve, Resize, Shrink, or any Pop*Shrink
+// call. It points to another element, or to a free slot, after the element is
+// popped, dropped, or moved by Insert, Delete, Replace, RemoveAt, Retain,
+// Rotate*, Reverse, MakeContiguous, or sorting. Pushing without growing does
+// not affect it.
+func (d *Deque[T]) PtrAt(i int) *T {
+	d.checkBounds(i)
+	return &d.buf[(d.head+uint(i))&d.mask]
+}
+
+// FrontPtr returns a pointer to the first element in the Deque, or nil if it's
+// empty. The pointer is invalidated as described in PtrAt.
+func (d *Deque[T]) FrontPtr() *T {
+	if d.Empty() {
+		return nil
+	}
+	return &d.buf[d.head&d.mask]
+}
+
+// BackPtr returns a pointer to the last element in the Deque, or nil if it's
+// empty. The pointer is invalidated as described in PtrAt.
+func (d *Deque[T]) BackPtr() *T {
+	if d.Empty() {
+		return nil
+	}
+	return &d.buf[(d.tail-1)&d.mask]
+}
+
+// PushBackSlot pushes a zero value at the back of the Deque and returns a
+// pointer to it, so that a large element can be built in place instead of
+// copied. It has the same semantics as PushBack, including reallocation and
+// eviction. The pointer is invalidated as described in PtrAt.
+func (d *Deque[T]) PushBackSlot() *T {
+	var zero T
+	d.PushBack(zero)
+	return d.BackPtr()
+}
+
+// PushFrontSlot pushes a zero value at the front of the Deque and returns a
+// pointer to it, so that a large element can be built in place instead of
+// copied. It has the same semantics as PushFront, including reallocation and
+// eviction. The pointer is invalidated as described in PtrAt.
+func (d *Deque[T]) PushFrontSlot() *T {
+	var zero T
+	d.PushFront(zero)
+	return d.FrontPtr()
+}
+
 // Swap swaps the elements in the i-th and j-th indexes. Panics if out of
 // bounds.
 func (d *Deque[T]) Swap(i, j int) {
@@ -499,10 +688,63 @@ func (d *Deque[T]) SwapUnsafe(i, j int) {
 	d.SetUnsafe(j, a)
 }
 
+// Insert inserts the elements at index i, in order, so that the first one ends
+// up at index i. It has the same semantics as slices.Insert, so it panics if
+// i is out of range, but it moves only the elements between i and the nearer
+// end, and reallocates at most once. If the Deque has a maximum length, the
+// overflow is evicted from the front.
+func (d *Deque[T]) Insert(i int, ts ...T) {
+	if i < 0 || i > d.Len() {
+		panic(fmt.Sprintf("deque: insert index %d out of range with length %d", i, d.Len()))
+	}
+	d.insert(uint(i), ts)
+}
+
+// Delete removes the elements from index i (inclusive) to index j
+// (non-inclusive). It has the same semantics as slices.Delete, so it panics
+// if the indexes are invalid, but it moves only the elements between the
+// deleted ones and the nearer end. The vacated slots are zeroed, allowing
+// garbage collection to occur.
+func (d *Deque[T]) Delete(i, j int) {
+	d.checkRange(i, j)
+	d.delete(uint(i), uint(j))
+}
+
+// Replace replaces the elements from index i (inclusive) to index j
+// (non-inclusive) with the given elements. It has the same semantics as
+// slices.Replace, so it panics if the indexes are invalid. Like Insert and
+// Delete, it moves only the elements between the replaced ones and the nearer
+// end, reallocates at most once, and zeroes vacated slots.
+func (d *Deque[T]) Replace(i, j int, ts ...T) {
+	d.checkRange(i, j)
+	k := min(j-i, len(ts))
+	for x, t := range ts[:k] {
+		d.SetUnsafe(i+x, t)
+	}
+	if len(ts) > k {
+		d.insert(uint(j), ts[k:])
+	} else {
+		d.delete(uint(i+k), uint(j))
+	}
+}
+
+// RemoveAt removes the i-th element and returns it. It moves only the elements
+// between i and the nearer end, and zeroes the vacated slot. Panics if out of
+// bounds.
+func (d *Deque[T]) RemoveAt(i int) T {
+	d.checkBounds(i)
+	t := d.AtUnsafe(i)
+	d.delete(uint(i), uint(i)+1)
+	return t
+}
+
 // ClearLazy empties the Deque in O(1), but does not zero the elements. If
 // references remain, the memory they point to will not be garbage collected.
 // Capacity is retained. This is useful for reusing a Deque with no references.
-func (d *Deque[T]) ClearLazy() { d.head, d.tail = 0, 0 }
+func (d *Deque[T]) ClearLazy() {
+	d.head, d.tail = 0, 0
+	d.mod++
+}
 
 // ClearEager empties the Deque in O(d.Len()), zeroing existing elements and
 // maintaining capacity. This is useful for reusing a Deque with references.
@@ -512,6 +754,7 @@ func (d *Deque[T]) ClearEager() {
 		d.buf[i&d.mask] = zero
 	}
 	d.head, d.tail = 0, 0
+	d.mod++
 }
 
 // Contains returns whether the element is in the Deque. This must not be a
@@ -538,23 +781,7 @@ func Equal[T comparable](d1 *Deque[T], d2 *Deque[T]) bool {
 	if d1 == nil || d2 == nil {
 		return d1 == d2
 	}
-
-	if d1.len() != d2.len() {
-		return false
-	}
-
-	s11, s12 := d1.slices()
-	s21, s22 := d2.slices()
-
-	if len(s11) <= len(s12) {
-		return slices.Equal(s11, s12[:len(s11)]) &&
-			slices.Equal(s12[len(s11):], s21[:len(s12)-len(s11)]) &&
-			slices.Equal(s21[len(s21)-len(s22):], s22)
-	} else {
-		return slices.Equal(s12, s11[:len(s12)]) &&
-			slices.Equal(s11[len(s12):], s22[:len(s11)-len(s12)]) &&
-			slices.Equal(s22[len(s22)-len(s21):], s21)
-	}
+	return d1.len() == d2.len() && lockstep(d1, d2, slices.Equal[[]T])
 }
 
 // EqualFunc returns whether both Deques have the same length and the same
@@ -565,23 +792,50 @@ func (d1 *Deque[T]) EqualFunc(d2 *Deque[T], f func(T, T) bool) bool {
 	if d1 == nil || d2 == nil {
 		return d1 == d2
 	}
+	return d1.len() == d2.len() && lockstep(d1, d2, func(a, b []T) bool {
+		return slices.EqualFunc(a, b, f)
+	})
+}
 
-	if d1.len() != d2.len() {
+// EqualSlice returns whether the Deque has the same length and the same
+// elements in the same order as s. A nil Deque is equal to an empty slice. It
+// must not be a method, otherwise Deque would be constrained to comparable
+// elements.
+func EqualSlice[T comparable](d *Deque[T], s []T) bool {
+	if d.Len() != len(s) {
 		return false
 	}
+	a, b := d.slices()
+	return slices.Equal(a, s[:len(a)]) && slices.Equal(b, s[len(a):])
+}
 
-	s11, s12 := d1.slices()
-	s21, s22 := d2.slices()
+// Compare compares the elements of both Deques in order, like slices.Compare.
+// The result is 0 if d1 == d2, -1 if d1 < d2, and +1 if d1 > d2. A nil Deque
+// is equal to an empty one. It must not be a method, otherwise Deque would be
+// constrained to ordered elements.
+func Compare[T cmp.Ordered](d1, d2 *Deque[T]) int {
+	return CompareFunc(d1, d2, cmp.Compare[T])
+}
 
-	if len(s11) <= len(s12) {
-		return slices.EqualFunc(s11, s12[:len(s11)], f) &&
-			slices.EqualFunc(s12[len(s11):], s21[:len(s12)-len(s11)], f) &&
-			slices.EqualFunc(s21[len(s21)-len(s22):], s22, f)
-	} else {
-		return slices.EqualFunc(s12, s11[:len(s12)], f) &&
-			slices.EqualFunc(s11[len(s12):], s22[:len(s11)-len(s12)], f) &&
-			slices.EqualFunc(s22[len(s22)-len(s21):], s21, f)
+// CompareFunc works like Compare, but uses a custom comparison function on
+// each pair of elements, like slices.CompareFunc. It must not be a method, so
+// that the Deques may hold different types.
+func CompareFunc[T, U any](d1 *Deque[T], d2 *Deque[U], cmp func(T, U) int) int {
+	result := 0
+	lockstep(d1, d2, func(a []T, b []U) bool {
+		result = slices.CompareFunc(a, b, cmp)
+		return result == 0
+	})
+	if result != 0 {
+		return result
 	}
+	switch {
+	case d1.Len() < d2.Len():
+		return -1
+	case d1.Len() > d2.Len():
+		return +1
+	}
+	return 0
 }
 
 // Index returns the index of the first ocurrence of t in the Deque or -1 if
@@ -618,7 +872,8 @@ func (d *Deque[T]) IndexFunc(f func(T) bool) int {
 
 // Max returns the maximum element in the queue. It must not be a method,
 // otherwise Deque would be constrained to comparable elements only. It has the
-// same semantics as slices.Max, so it panics on an empty Deque.
+// same semantics as slices.Max, so it panics on an empty Deque. Use MaxOK to
+// avoid panicking.
 func Max[T cmp.Ordered](d *Deque[T]) T {
 	s1, s2 := d.slices()
 	result := slices.Max(s1)
@@ -629,22 +884,36 @@ func Max[T cmp.Ordered](d *Deque[T]) T {
 	return result
 }
 
-// MaxFunc returns the maximum element in the queue. It must not be a method,
-// otherwise Deque would be constrained to comparable elements only. It has the
-// same semantics as slices.MaxFunc, so it panics on an empty Deque.
-func MaxFunc[T cmp.Ordered](d *Deque[T], cmp func(T, T) int) T {
+// MaxOK returns the maximum element in the queue, like Max. If the Deque is
+// empty, it returns false instead of panicking.
+func MaxOK[T cmp.Ordered](d *Deque[T]) (t T, ok bool) {
+	if d.Len() == 0 {
+		return
+	}
+	return Max(d), true
+}
+
+// MaxFunc returns the maximum element in the queue as determined by cmp. If
+// there are several, it returns the first. It is a function for consistency
+// with Max. It has the same semantics as slices.MaxFunc, so it panics on an
+// empty Deque.
+func MaxFunc[T any](d *Deque[T], cmp func(T, T) int) T {
 	s1, s2 := d.slices()
 	result := slices.MaxFunc(s1, cmp)
 	// slices.MaxFunc panics on an empty slice, so handle this edge case.
 	if s2 != nil {
-		result = max(result, slices.MaxFunc(s2, cmp))
+		// Keep the first maximum on ties, like slices.MaxFunc.
+		if m := slices.MaxFunc(s2, cmp); cmp(m, result) > 0 {
+			result = m
+		}
 	}
 	return result
 }
 
 // Min returns the minimum element in the queue. It must not be a method,
 // otherwise Deque would be constrained to comparable elements only. It has the
-// same semantics as slices.Min, so it panics on an empty Deque.
+// same semantics as slices.Min, so it panics on an empty Deque. Use MinOK to
+// avoid panicking.
 func Min[T cmp.Ordered](d *Deque[T]) T {
 	s1, s2 := d.slices()
 	result := slices.Min(s1)
@@ -655,89 +924,603 @@ func Min[T cmp.Ordered](d *Deque[T]) T {
 	return result
 }
 
-// MinFunc returns the minimum element in the queue. It must not be a method,
-// otherwise Deque would be constrained to comparable elements only. It has the
-// same semantics as slices.MinFunc, so it panics on an empty Deque.
-func MinFunc[T cmp.Ordered](d *Deque[T], cmp func(T, T) int) T {
+// MinOK returns the minimum element in the queue, like Min. If the Deque is
+// empty, it returns false instead of panicking.
+func MinOK[T cmp.Ordered](d *Deque[T]) (t T, ok bool) {
+	if d.Len() == 0 {
+		return
+	}
+	return Min(d), true
+}
+
+// MinFunc returns the minimum element in the queue as determined by cmp. If
+// there are several, it returns the first. It is a function for consistency
+// with Min. It has the same semantics as slices.MinFunc, so it panics on an
+// empty Deque.
+func MinFunc[T any](d *Deque[T], cmp func(T, T) int) T {
 	s1, s2 := d.slices()
 	result := slices.MinFunc(s1, cmp)
 	// slices.MinFunc panics on an empty slice, so handle this edge case.
 	if s2 != nil {
-		result = min(result, slices.MinFunc(s2, cmp))
+		// Keep the first minimum on ties, like slices.MinFunc.
+		if m := slices.MinFunc(s2, cmp); cmp(m, result) < 0 {
+			result = m
+		}
 	}
 	return result
 }
 
+// ArgMax returns the index of the maximum element in the queue, or -1 if it's
+// empty. If there are several, it returns the first. Elements are compared
+// with cmp.Compare, so a NaN is less than any other value. It must not be a
+// method, otherwise Deque would be constrained to ordered elements.
+func ArgMax[T cmp.Ordered](d *Deque[T]) int {
+	return ArgMaxFunc(d, cmp.Compare[T])
+}
+
+// ArgMaxFunc returns the index of the maximum element in the queue as
+// determined by cmp, or -1 if it's empty. If there are several, it returns the
+// first.
+func ArgMaxFunc[T any](d *Deque[T], cmp func(T, T) int) int {
+	return argBest(d, func(a, b T) bool { return cmp(a, b) > 0 })
+}
+
+// ArgMin returns the index of the minimum element in the queue, or -1 if it's
+// empty. If there are several, it returns the first. Elements are compared
+// with cmp.Compare, so a NaN is less than any other value. It must not be a
+// method, otherwise Deque would be constrained to ordered elements.
+func ArgMin[T cmp.Ordered](d *Deque[T]) int {
+	return ArgMinFunc(d, cmp.Compare[T])
+}
+
+// ArgMinFunc returns the index of the minimum element in the queue as
+// determined by cmp, or -1 if it's empty. If there are several, it returns the
+// first.
+func ArgMinFunc[T any](d *Deque[T], cmp func(T, T) int) int {
+	return argBest(d, func(a, b T) bool { return cmp(a, b) < 0 })
+}
+
+// MinMax returns both the minimum and the maximum elements in the queue in a
+// single pass. If the Deque is empty, it returns false. Elements are compared
+// with cmp.Compare, so a NaN is less than any other value. It must not be a
+// method, otherwise Deque would be constrained to ordered elements.
+func MinMax[T cmp.Ordered](d *Deque[T]) (minimum, maximum T, ok bool) {
+	return MinMaxFunc(d, cmp.Compare[T])
+}
+
+// MinMaxFunc returns both the minimum and the maximum elements in the queue as
+// determined by cmp, in a single pass. If there are several, it returns the
+// first of each. If the Deque is empty, it returns false.
+func MinMaxFunc[T any](d *Deque[T], cmp func(T, T) int) (minimum, maximum T, ok bool) {
+	s1, s2 := d.slices()
+	for _, s := range [2][]T{s1, s2} {
+		for _, t := range s {
+			switch {
+			case !ok:
+				minimum, maximum, ok = t, t, true
+			case cmp(t, minimum) < 0:
+				minimum = t
+			case cmp(t, maximum) > 0:
+				maximum = t
+			}
+		}
+	}
+	return
+}
+
 // ForEach takes in a function that returns a bool and calls it in order for
 // every element in the queue, or until the first call that returns false.
+// Panics if f structurally modifies the Deque.
 func (d *Deque[T]) ForEach(f func(T) bool) {
+	mod := d.mod
 	s1, s2 := d.slices()
 	for _, t := range s1 {
 		if !f(t) {
 			return
 		}
+		d.checkMod(mod)
 	}
 	for _, t := range s2 {
 		if !f(t) {
 			return
 		}
+		d.checkMod(mod)
 	}
 }
 
 // All returns an iterator over index-value pairs in order. It has the same
 // semantics as slices.All. If you don't need indexes, use Iter instead.
-// Does not panic if modified during iteration.
+// Panics if the Deque is structurally modified during iteration.
 func (d *Deque[T]) All() iter.Seq2[int, T] {
 	// TODO: replace this with slices.All?
 	return func(yield func(int, T) bool) {
 		if d == nil {
 			return
 		}
+		mod := d.mod
 		s1, s2 := d.slices()
-		var i int
-		var t T
-		for i, t = range s1 {
+		for i, t := range s1 {
 			if !yield(i, t) {
 				return
 			}
+			d.checkMod(mod)
 		}
-		for _, t = range s2 {
-			if !yield(i, t) {
+		for i, t := range s2 {
+			if !yield(len(s1)+i, t) {
 				return
 			}
-			i++
+			d.checkMod(mod)
 		}
 	}
 }
 
-// TODO: Rotate,  more of the slices package?
+// RotateLeft rotates the Deque k positions to the left, so that the element at
+// index k becomes the first one. It takes O(min(k, n-k)) by moving elements
+// between the ends of the ring, wrapping k around the length. A negative k
+// rotates to the right.
+func (d *Deque[T]) RotateLeft(k int) {
+	n := d.Len()
+	if n == 0 {
+		return
+	}
+	k %= n
+	if k < 0 {
+		k += n
+	}
+	if k <= n-k {
+		d.rotateFrontToBack(uint(k))
+	} else {
+		d.rotateBackToFront(uint(n - k))
+	}
+}
+
+// RotateRight rotates the Deque k positions to the right, so that the element
+// at index n-k becomes the first one. It has the same semantics as
+// RotateLeft(-k).
+func (d *Deque[T]) RotateRight(k int) {
+	n := d.Len()
+	if n == 0 {
+		return
+	}
+	d.RotateLeft(n - k%n)
+}
+
+// Moves k elements from the front to the back, one at a time.
+func (d *Deque[T]) rotateFrontToBack(k uint) {
+	// In a full ring, the front and back slots coincide, so there is no
+	// stale copy to zero.
+	full := d.len() == d.cap()
+	var zero T
+	for range k {
+		d.buf[d.tail&d.mask] = d.buf[d.head&d.mask]
+		if !full {
+			d.buf[d.head&d.mask] = zero
+		}
+		d.head++
+		d.tail++
+	}
+	d.mod++
+}
+
+// Moves k elements from the back to the front, one at a time.
+func (d *Deque[T]) rotateBackToFront(k uint) {
+	full := d.len() == d.cap()
+	var zero T
+	for range k {
+		d.head--
+		d.tail--
+		d.buf[d.head&d.mask] = d.buf[d.tail&d.mask]
+		if !full {
+			d.buf[d.tail&d.mask] = zero
+		}
+	}
+	d.mod++
+}
+
+// Reverse reverses the elements of the Deque in place, across the wraparound.
+// It has the same semantics as slices.Reverse.
+func (d *Deque[T]) Reverse() {
+	for i, j := d.head, d.tail-1; i != j && i != j+1; i, j = i+1, j-1 {
+		a, b := &d.buf[i&d.mask], &d.buf[j&d.mask]
+		*a, *b = *b, *a
+	}
+}
+
+// MakeContiguous rearranges the underlying buffer so that every element is
+// stored in a single slice, which it returns without copying, like Rust's
+// VecDeque::make_contiguous. The slice shares memory with the Deque, so it can
+// be sorted or passed to functions that take slices in place. It is only valid
+// until the Deque is structurally modified.
+//
+// If the elements wrap around the end of the buffer, it moves the shorter part
+// when the free space allows, and rotates the whole buffer otherwise. It never
+// allocates.
+func (d *Deque[T]) MakeContiguous() []T {
+	n := d.len()
+	h := d.head & d.mask
+	if h+n <= d.cap() {
+		return d.buf[h : h+n]
+	}
+
+	la := d.cap() - h // Length of the front part, at the end of buf.
+	lb := n - la      // Length of the back part, at the start of buf.
+	free := d.cap() - n
+	switch {
+	case la <= free:
+		// Shift the back part right and move the front part before it.
+		copy(d.buf[la:la+lb], d.buf[:lb])
+		copy(d.buf[:la], d.buf[h:])
+		clear(d.buf[h:])
+		h = 0
+	case lb <= free:
+		// Shift the front part left and move the back part after it.
+		copy(d.buf[h-lb:], d.buf[h:])
+		copy(d.buf[d.cap()-lb:], d.buf[:lb])
+		clear(d.buf[:lb])
+		h -= lb
+	default:
+		// Rotate the whole buffer left by h, with three reversals.
+		slices.Reverse(d.buf[:h])
+		slices.Reverse(d.buf[h:])
+		slices.Reverse(d.buf)
+		h = 0
+	}
+	d.head = h
+	d.tail = h + n
+	d.mod++
+	return d.buf[h : h+n]
+}
+
+// Retain keeps only the elements for which f returns true, in order, and
+// returns how many were removed. It makes a single pass across the ring and
+// zeroes the freed slots, allowing garbage collection to occur.
+func (d *Deque[T]) Retain(f func(T) bool) int {
+	n := d.len()
+	var w uint
+	for r := range n {
+		t := d.buf[(d.head+r)&d.mask]
+		if f(t) {
+			if w != r {
+				d.buf[(d.head+w)&d.mask] = t
+			}
+			w++
+		}
+	}
+	if w != n {
+		d.DropBackZero(int(n - w))
+	}
+	return int(n - w)
+}
+
+// DeleteFunc removes the elements for which f returns true and returns how
+// many were removed. It has the same semantics as slices.DeleteFunc, and the
+// same performance as Retain.
+func (d *Deque[T]) DeleteFunc(f func(T) bool) int {
+	return d.Retain(func(t T) bool { return !f(t) })
+}
+
+// Compact replaces consecutive runs of equal elements with a single copy, like
+// Rust's Vec::dedup, and returns how many were removed. It must not be a
+// method, otherwise Deque would be constrained to comparable elements only.
+// It has the same semantics as slices.Compact, and the same performance as
+// Retain.
+func Compact[T comparable](d *Deque[T]) int {
+	return d.CompactFunc(func(a, b T) bool { return a == b })
+}
+
+// CompactFunc works like Compact, but uses an equality function to compare
+// elements. For runs of elements that compare equal, it keeps the first one.
+// It has the same semantics as slices.CompactFunc, so eq is called with each
+// element and the one before it, in that order.
+func (d *Deque[T]) CompactFunc(eq func(T, T) bool) int {
+	first := true
+	var prev T
+	return d.Retain(func(t T) bool {
+		keep := first || !eq(t, prev)
+		first = false
+		prev = t
+		return keep
+	})
+}
+
+// TODO: more of the slices package?
+
+/*****************************************************************************
+ * SORT API
+ *****************************************************************************/
+
+// Sort sorts the Deque in place in ascending order. It must not be a method,
+// otherwise Deque would be constrained to ordered elements only. It has the
+// same semantics as slices.Sort, after making the Deque contiguous.
+func Sort[T cmp.Ordered](d *Deque[T]) {
+	slices.Sort(d.MakeContiguous())
+}
+
+// SortFunc sorts the Deque in place in ascending order as determined by cmp.
+// It has the same semantics as slices.SortFunc, after making the Deque
+// contiguous.
+func (d *Deque[T]) SortFunc(cmp func(a, b T) int) {
+	slices.SortFunc(d.MakeContiguous(), cmp)
+}
+
+// SortStableFunc sorts the Deque in place in ascending order as determined by
+// cmp, keeping the original order of equal elements. It has the same
+// semantics as slices.SortStableFunc, after making the Deque contiguous.
+func (d *Deque[T]) SortStableFunc(cmp func(a, b T) int) {
+	slices.SortStableFunc(d.MakeContiguous(), cmp)
+}
+
+// IsSorted returns whether the Deque is sorted in ascending order. It must not
+// be a method, otherwise Deque would be constrained to ordered elements only.
+// It has the same semantics as slices.IsSorted.
+func IsSorted[T cmp.Ordered](d *Deque[T]) bool {
+	return d.IsSortedFunc(cmp.Compare[T])
+}
+
+// IsSortedFunc returns whether the Deque is sorted in ascending order as
+// determined by cmp. It has the same semantics as slices.IsSortedFunc.
+func (d *Deque[T]) IsSortedFunc(cmp func(a, b T) int) bool {
+	s1, s2 := d.slices()
+	if len(s2) > 0 && cmp(s2[0], s1[len(s1)-1]) < 0 {
+		return false
+	}
+	return slices.IsSortedFunc(s1, cmp) && slices.IsSortedFunc(s2, cmp)
+}
+
+// BinarySearch searches for target in a sorted Deque and returns the earliest
+// position where target is found, or the position where it would be
+// inserted, and whether it was found. It must not be a method, otherwise Deque
+// would be constrained to ordered elements only. It has the same semantics as
+// slices.BinarySearch.
+func BinarySearch[T cmp.Ordered](d *Deque[T], target T) (int, bool) {
+	return BinarySearchFunc(d, target, cmp.Compare[T])
+}
+
+// BinarySearchFunc works like BinarySearch, but uses a custom comparison
+// function. It must not be a method, because methods cannot have their own
+// type parameters. It has the same semantics as slices.BinarySearchFunc.
+func BinarySearchFunc[E, T any](d *Deque[E], target T, cmp func(E, T) int) (int, bool) {
+	s1, s2 := d.slices()
+	if len(s2) > 0 && cmp(s2[0], target) < 0 {
+		i, found := slices.BinarySearchFunc(s2, target, cmp)
+		return len(s1) + i, found
+	}
+	i, found := slices.BinarySearchFunc(s1, target, cmp)
+	if i == len(s1) && len(s2) > 0 {
+		found = cmp(s2[0], target) == 0
+	}
+	return i, found
+}
+
+// PartitionPoint returns the index of the first element for which pred is
+// false, assuming the Deque is partitioned so that pred is true for a prefix
+// and false for the rest, like Rust's slice::partition_point. If pred is true
+// for every element, it returns d.Len().
+func (d *Deque[T]) PartitionPoint(pred func(T) bool) int {
+	s1, s2 := d.slices()
+	if len(s2) > 0 && pred(s2[0]) {
+		return len(s1) + partitionPoint(s2, pred)
+	}
+	return partitionPoint(s1, pred)
+}
+
+// InsertSorted inserts t into a sorted Deque, keeping it sorted, and returns
+// its index. It is inserted after any equal elements. It must not be a
+// method, otherwise Deque would be constrained to ordered elements only. Like
+// Insert, it moves only the elements between the index and the nearer end.
+//
+// If the Deque has a maximum length, the overflow is evicted from the front,
+// and the returned index accounts for it. If t itself is evicted because it
+// sorts before every other element of a full ring, it returns -1.
+func InsertSorted[T cmp.Ordered](d *Deque[T], t T) int {
+	return d.InsertSortedFunc(t, cmp.Compare[T])
+}
+
+// InsertSortedFunc works like InsertSorted, but uses a custom comparison
+// function, with the same semantics as the one in slices.SortFunc.
+func (d *Deque[T]) InsertSortedFunc(t T, cmp func(a, b T) int) int {
+	i := d.PartitionPoint(func(e T) bool { return cmp(e, t) <= 0 })
+	evicted := d.insert(uint(i), []T{t})
+	if uint(i) < evicted {
+		return -1
+	}
+	return i - int(evicted)
+}
 
 /*****************************************************************************
  * ITER API
  *****************************************************************************/
 
 // Iter returns an iterator over values only in order. If you need indexes,
-// use All instead. Does not panic if the Deque is modified during iteration.
+// use All instead. Panics if the Deque is structurally modified during
+// iteration.
 func (d *Deque[T]) Iter() iter.Seq[T] {
 	return func(yield func(T) bool) {
 		if d == nil {
 			return
 		}
+		mod := d.mod
 		s1, s2 := d.slices()
 		for _, t := range s1 {
 			if !yield(t) {
 				return
 			}
+			d.checkMod(mod)
 		}
 		for _, t := range s2 {
 			if !yield(t) {
 				return
 			}
+			d.checkMod(mod)
 		}
 	}
 }
 
-// TODO: RIter, IterPopFront, IterPopBack, IterPopFrontZero, IterPopBackZero
+// RIter returns an iterator over values only in reverse order, from back to
+// front. If you need indexes, use Backward instead. Panics if the Deque is
+// structurally modified during iteration.
+func (d *Deque[T]) RIter() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if d == nil {
+			return
+		}
+		mod := d.mod
+		s1, s2 := d.slices()
+		for i := len(s2) - 1; i >= 0; i-- {
+			if !yield(s2[i]) {
+				return
+			}
+			d.checkMod(mod)
+		}
+		for i := len(s1) - 1; i >= 0; i-- {
+			if !yield(s1[i]) {
+				return
+			}
+			d.checkMod(mod)
+		}
+	}
+}
+
+// Backward returns an iterator over index-value pairs in reverse order, from
+// back to front. It has the same semantics as slices.Backward. If you don't
+// need indexes, use RIter instead. Panics if the Deque is structurally
+// modified during iteration.
+func (d *Deque[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		if d == nil {
+			return
+		}
+		mod := d.mod
+		s1, s2 := d.slices()
+		for i := len(s2) - 1; i >= 0; i-- {
+			if !yield(len(s1)+i, s2[i]) {
+				return
+			}
+			d.checkMod(mod)
+		}
+		for i := len(s1) - 1; i >= 0; i-- {
+			if !yield(i, s1[i]) {
+				return
+			}
+			d.checkMod(mod)
+		}
+	}
+}
+
+// IterPopFront returns an iterator that pops elements from the front and
+// yields them, until the Deque is empty. Each element is popped before it's
+// yielded, so breaking out of the loop leaves the remaining elements in the
+// Deque. The loop body may push, for example to traverse a graph breadth
+// first. Like PopFront, it does not zero the popped slots.
+func (d *Deque[T]) IterPopFront() iter.Seq[T] {
+	return d.iterPop((*Deque[T]).PopFrontUnsafe)
+}
+
+// IterPopFrontZero is IterPopFront, but zeroes the popped slots like
+// PopFrontZero, allowing garbage collection to occur.
+func (d *Deque[T]) IterPopFrontZero() iter.Seq[T] {
+	return d.iterPop((*Deque[T]).PopFrontZeroUnsafe)
+}
+
+// IterPopBack returns an iterator that pops elements from the back and yields
+// them, until the Deque is empty. Each element is popped before it's yielded,
+// so breaking out of the loop leaves the remaining elements in the Deque. The
+// loop body may push, for example to traverse a graph depth first. Like
+// PopBack, it does not zero the popped slots.
+func (d *Deque[T]) IterPopBack() iter.Seq[T] {
+	return d.iterPop((*Deque[T]).PopBackUnsafe)
+}
+
+// IterPopBackZero is IterPopBack, but zeroes the popped slots like
+// PopBackZero, allowing garbage collection to occur.
+func (d *Deque[T]) IterPopBackZero() iter.Seq[T] {
+	return d.iterPop((*Deque[T]).PopBackZeroUnsafe)
+}
+
+// Iterator is a forward iterator over a Deque that allows removing elements
+// during iteration, which makes the other iterators panic. Like them, it
+// panics if the Deque is structurally modified other than through the
+// Iterator. Use it as follows:
+//
+//	for it := d.Iterator(); it.Next(); {
+//		if done(it.Value()) {
+//			it.Remove()
+//		}
+//	}
+type Iterator[T any] struct {
+	d     *Deque[T]
+	next  uint
+	cur   uint
+	valid bool
+	mod   uint
+}
+
+// Iterator returns an Iterator positioned before the first element.
+func (d *Deque[T]) Iterator() *Iterator[T] {
+	return &Iterator[T]{d: d, mod: d.mod}
+}
+
+// Next advances the Iterator to the next element and returns whether there is
+// one.
+func (it *Iterator[T]) Next() bool {
+	it.d.checkMod(it.mod)
+	it.valid = it.next < it.d.len()
+	if it.valid {
+		it.cur = it.next
+		it.next++
+	}
+	return it.valid
+}
+
+// Index returns the index of the current element. Panics if there is none.
+func (it *Iterator[T]) Index() int {
+	it.checkValid()
+	return int(it.cur)
+}
+
+// Value returns the current element. Panics if there is none.
+func (it *Iterator[T]) Value() T {
+	it.checkValid()
+	return it.d.AtUnsafe(int(it.cur))
+}
+
+// Set overwrites the current element. Panics if there is none.
+func (it *Iterator[T]) Set(t T) {
+	it.checkValid()
+	it.d.SetUnsafe(int(it.cur), t)
+}
+
+// Remove removes the current element from the Deque, shifting whichever side
+// is shorter and zeroing the vacated slot. There is no current element until
+// the next call to Next. Panics if there is none.
+func (it *Iterator[T]) Remove() {
+	it.checkValid()
+	it.d.delete(it.cur, it.cur+1)
+	it.mod = it.d.mod
+	it.next--
+	it.valid = false
+}
+
+func (it *Iterator[T]) checkValid() {
+	it.d.checkMod(it.mod)
+	if !it.valid {
+		panic("deque: Iterator has no current element")
+	}
+}
+
+// Internal implementation for the IterPop* iterators.
+func (d *Deque[T]) iterPop(pop func(*Deque[T]) T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if d == nil {
+			return
+		}
+		for !d.Empty() {
+			if !yield(pop(d)) {
+				return
+			}
+		}
+	}
+}
 
 /*****************************************************************************
  * SENTINEL ERRORS
@@ -755,10 +1538,30 @@ var ErrNotEnoughCapacity = errors.New("cannot hold existing elements in asked ca
 // capacity.
 var ErrNegativeCapacity = errors.New("capacity cannot be negative")
 
+// ErrClosed is returned when waiting on a closed and empty SyncDeque or
+// BoundedDeque, or when pushing to a closed BoundedDeque without panicking.
+var ErrClosed = errors.New("deque is closed")
+
+// ErrFull is returned when trying to push to a BoundedDeque without room for
+// every element.
+var ErrFull = errors.New("deque is full")
+
+// ErrInvalidMaxLen is returned when trying to set a maximum length that is
+// negative, or zero where a limit is required.
+var ErrInvalidMaxLen = errors.New("invalid maximum length")
+
+// ErrInvalidWindow is returned when the time window of a TimedDeque is not
+// positive.
+var ErrInvalidWindow = errors.New("window must be positive")
+
 /*****************************************************************************
  * HELPERS
  *****************************************************************************/
 
+// cacheLinePad separates fields written by different goroutines so that they
+// don't share a cache line, avoiding false sharing.
+type cacheLinePad [64]byte
+
 func ceilPow2(x uint) uint {
 	// For our purposes, 0 is invalid.
 	if x == 0 {
@@ -773,6 +1576,156 @@ func ceilPow2(x uint) uint {
 	return result
 }
 
+func partitionPoint[T any](s []T, pred func(T) bool) int {
+	return sort.Search(len(s), func(i int) bool { return !pred(s[i]) })
+}
+
+// argBest returns the index of the first element that no other element is
+// better than, or -1 if the Deque is empty.
+func argBest[T any](d *Deque[T], better func(a, b T) bool) int {
+	s1, s2 := d.slices()
+	result := -1
+	var best T
+	for i, t := range s1 {
+		if result == -1 || better(t, best) {
+			result, best = i, t
+		}
+	}
+	for i, t := range s2 {
+		if result == -1 || better(t, best) {
+			result, best = len(s1)+i, t
+		}
+	}
+	return result
+}
+
+// lockstep walks both Deques from front to back at once, calling f with
+// slices of the same length at the same positions, split wherever either
+// Deque wraps around. It stops when the shorter Deque ends, or when f returns
+// false, and returns whether f always returned true.
+func lockstep[T, U any](d1 *Deque[T], d2 *Deque[U], f func([]T, []U) bool) bool {
+	a1, a2 := d1.slices()
+	b1, b2 := d2.slices()
+	for len(a1) > 0 && len(b1) > 0 {
+		n := min(len(a1), len(b1))
+		if !f(a1[:n], b1[:n]) {
+			return false
+		}
+		a1, b1 = a1[n:], b1[n:]
+		if len(a1) == 0 {
+			a1, a2 = a2, nil
+		}
+		if len(b1) == 0 {
+			b1, b2 = b2, nil
+		}
+	}
+	return true
+}
+
+func (d *Deque[T]) checkMod(mod uint) {
+	if d.mod != mod {
+		panic("deque: Deque modified during iteration")
+	}
+}
+
+func (d *Deque[T]) checkRange(i, j int) {
+	if i < 0 || j < i || j > d.Len() {
+		panic(fmt.Sprintf("deque: range [%d:%d] out of bounds with length %d", i, j, d.Len()))
+	}
+}
+
+// delete removes the elements in [i, j), moving whichever side of the ring is
+// shorter and zeroing the vacated slots. Indexes must be within bounds.
+func (d *Deque[T]) delete(i, j uint) {
+	n := d.len()
+	k := j - i
+	if k == 0 {
+		return
+	}
+	var zero T
+	if i < n-j {
+		// Shift the front right, over the deleted elements.
+		for x := i; x > 0; x-- {
+			d.buf[(d.head+x-1+k)&d.mask] = d.buf[(d.head+x-1)&d.mask]
+		}
+		for x := range k {
+			d.buf[(d.head+x)&d.mask] = zero
+		}
+		d.head += k
+	} else {
+		// Shift the back left, over the deleted elements.
+		for x := j; x < n; x++ {
+			d.buf[(d.head+x-k)&d.mask] = d.buf[(d.head+x)&d.mask]
+		}
+		for x := n - k; x < n; x++ {
+			d.buf[(d.head+x)&d.mask] = zero
+		}
+		d.tail -= k
+	}
+	d.mod++
+}
+
+// insert puts ts at index i, in order, moving whichever side of the ring is
+// shorter. It reallocates at most once. If the Deque has a maximum length, the
+// overflow is evicted from the front before anything moves, so the buffer
+// never grows past it, and insert returns how many elements were evicted. i
+// must be within [0, d.Len()].
+func (d *Deque[T]) insert(i uint, ts []T) (evicted uint) {
+	k := uint(len(ts))
+	if k == 0 {
+		return 0
+	}
+	if d.maxLen != 0 && d.len()+k > d.maxLen {
+		// Evict in the order the elements would leave the front after
+		// inserting: those before i, then the first of ts. Since the Deque
+		// holds at most maxLen elements, the overflow never reaches past ts.
+		evicted = d.len() + k - d.maxLen
+		front := min(evicted, i)
+		for range front {
+			d.evict(d.PopFrontZeroUnsafe())
+		}
+		i -= front
+		for _, t := range ts[:evicted-front] {
+			d.evict(t)
+		}
+		ts = ts[evicted-front:]
+		k = uint(len(ts))
+		if k == 0 {
+			return evicted
+		}
+	}
+	n := d.len()
+	switch {
+	case n+k > d.cap():
+		// Reallocate, leaving a gap for ts instead of shifting twice.
+		newCap := ceilPow2(n + k)
+		newBuf := make([]T, newCap)
+		d.CopySlice(0, newBuf[:i])
+		d.CopySlice(int(i), newBuf[i+k:n+k])
+		d.buf = newBuf
+		d.head = 0
+		d.tail = n + k
+		d.mask = newCap - 1
+	case i < n-i:
+		// Shift the front left, opening a gap before index i.
+		d.head -= k
+		for x := range i {
+			d.buf[(d.head+x)&d.mask] = d.buf[(d.head+x+k)&d.mask]
+		}
+	default:
+		// Shift the back right, opening a gap at index i.
+		for x := n; x > i; x-- {
+			d.buf[(d.head+x-1+k)&d.mask] = d.buf[(d.head+x-1)&d.mask]
+		}
+		d.tail += k
+	}
+	for x, t := range ts {
+		d.buf[(d.head+i+uint(x))&d.mask] = t
+	}
+	d.mod++
+	return evicted
+}
+
 func (d *Deque[T]) checkBounds(i int) {
 	if i < 0 || i >= d.Len() {
 		panic(fmt.Sprintf("deque: index %d out of bounds with length %d", i, d.Len()))
@@ -0,0 +1,511 @@
+// Package dequetest checks implementations of deque.Interface against a plain
+// slice model. Use it to test a new implementation, or a wrapper around an
+// existing one:
+//
+//	func TestConformance(t *testing.T) {
+//		dequetest.RunConformance(t, func() deque.Interface[int] {
+//			return deque.MakeDeque[int]()
+//		})
+//	}
+package dequetest
+
+import (
+	"errors"
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// RunConformance runs the conformance suite as subtests of t. factory must
+// return a new, empty implementation on every call.
+func RunConformance(t *testing.T, factory func() deque.Interface[int]) {
+	t.Run("PushPop", func(t *testing.T) { testPushPop(t, factory()) })
+	t.Run("AtSet", func(t *testing.T) { testAtSet(t, factory()) })
+	t.Run("Drop", func(t *testing.T) { testDrop(t, factory) })
+	t.Run("Resize", func(t *testing.T) { testResize(t, factory()) })
+	t.Run("Iterators", func(t *testing.T) { testIterators(t, factory) })
+	t.Run("IterPop", func(t *testing.T) { testIterPop(t, factory) })
+	t.Run("Wraparound", func(t *testing.T) { testWraparound(t, factory) })
+	t.Run("Random", func(t *testing.T) { testRandom(t, factory()) })
+}
+
+// check compares d against the model element by element.
+func check(t *testing.T, d deque.Interface[int], model []int) {
+	t.Helper()
+	if d.Len() != len(model) {
+		t.Fatalf("Len() = %d, want %d", d.Len(), len(model))
+	}
+	if d.Empty() != (len(model) == 0) {
+		t.Fatalf("Empty() = %t with length %d", d.Empty(), len(model))
+	}
+	if d.Cap() < d.Len() {
+		t.Fatalf("Cap() = %d is less than Len() = %d", d.Cap(), d.Len())
+	}
+	for i, want := range model {
+		if got := d.At(i); got != want {
+			t.Fatalf("At(%d) = %d, want %d", i, got, want)
+		}
+	}
+	if got := d.MakeSliceCopy(); !slices.Equal(got, model) {
+		t.Fatalf("MakeSliceCopy() = %v, want %v", got, model)
+	}
+	front, ok := d.PeekFront()
+	if ok != (len(model) > 0) || ok && front != model[0] {
+		t.Fatalf("PeekFront() = %d, %t with model %v", front, ok, model)
+	}
+	back, ok := d.PeekBack()
+	if ok != (len(model) > 0) || ok && back != model[len(model)-1] {
+		t.Fatalf("PeekBack() = %d, %t with model %v", back, ok, model)
+	}
+}
+
+// fill pushes n elements at the back, starting at start.
+func fill(d deque.Interface[int], model []int, start, n int) []int {
+	for i := range n {
+		d.PushBack(start + i)
+		model = append(model, start+i)
+	}
+	return model
+}
+
+// sizes are lengths around the capacity and block boundaries of common
+// implementations.
+var sizes = []int{0, 1, 2, 15, 16, 17, 127, 128, 129, 300}
+
+func testPushPop(t *testing.T, d deque.Interface[int]) {
+	var model []int
+	d.PushBack(1, 2, 3)
+	model = append(model, 1, 2, 3)
+	check(t, d, model)
+	d.PushFront(4, 5, 6)
+	model = append([]int{6, 5, 4}, model...)
+	check(t, d, model)
+
+	pops := []struct {
+		name string
+		pop  func() (int, bool)
+		back bool
+	}{
+		{"PopBack", d.PopBack, true},
+		{"PopBackZero", d.PopBackZero, true},
+		{"PopBackShrink", d.PopBackShrink, true},
+		{"PopFront", d.PopFront, false},
+		{"PopFrontZero", d.PopFrontZero, false},
+		{"PopFrontShrink", d.PopFrontShrink, false},
+	}
+	for _, p := range pops {
+		model = fill(d, model, 100, 200)
+		for len(model) > 0 {
+			var want int
+			if p.back {
+				want, model = model[len(model)-1], model[:len(model)-1]
+			} else {
+				want, model = model[0], model[1:]
+			}
+			if got, ok := p.pop(); !ok || got != want {
+				t.Fatalf("%s() = %d, %t, want %d, true", p.name, got, ok, want)
+			}
+		}
+		check(t, d, model)
+		if got, ok := p.pop(); ok {
+			t.Fatalf("%s() on empty = %d, true", p.name, got)
+		}
+	}
+
+	model = fill(d, model, 0, 10)
+	for len(model) > 4 {
+		want := []int{model[0], model[1], model[len(model)-1], model[len(model)-2]}
+		got := []int{d.PopFrontUnsafe(), d.PopFrontZeroUnsafe(), d.PopBackUnsafe(), d.PopBackZeroUnsafe()}
+		if !slices.Equal(got, want) {
+			t.Fatalf("Pop*Unsafe() = %v, want %v", got, want)
+		}
+		model = model[2 : len(model)-2]
+		check(t, d, model)
+	}
+
+	*d.PushBackSlot() = 7
+	*d.PushFrontSlot() = 8
+	model = append(append([]int{8}, model...), 7)
+	check(t, d, model)
+	if *d.FrontPtr() != 8 || *d.BackPtr() != 7 {
+		t.Fatalf("FrontPtr() and BackPtr() = %d, %d, want 8, 7", *d.FrontPtr(), *d.BackPtr())
+	}
+	d.ClearEager()
+	if d.FrontPtr() != nil || d.BackPtr() != nil {
+		t.Fatal("FrontPtr() and BackPtr() are not nil on empty")
+	}
+	check(t, d, nil)
+}
+
+func testAtSet(t *testing.T, d deque.Interface[int]) {
+	var model []int
+	for i := range 200 {
+		d.PushFront(-i)
+		model = append([]int{-i}, model...)
+	}
+	model = fill(d, model, 0, 200)
+	for i := range model {
+		d.Set(i, i*i)
+		model[i] = i * i
+	}
+	check(t, d, model)
+	for i := range model {
+		d.SetUnsafe(i, i)
+		*d.PtrAt(i) += i
+		model[i] = 2 * i
+		if d.AtUnsafe(i) != model[i] {
+			t.Fatalf("AtUnsafe(%d) = %d, want %d", i, d.AtUnsafe(i), model[i])
+		}
+	}
+	check(t, d, model)
+	for i := range model {
+		j := len(model) - 1 - i
+		if i < j {
+			d.Swap(i, j)
+			model[i], model[j] = model[j], model[i]
+		}
+	}
+	check(t, d, model)
+	d.SwapUnsafe(0, 1)
+	model[0], model[1] = model[1], model[0]
+	check(t, d, model)
+
+	for _, i := range []int{-1, len(model)} {
+		mustPanic(t, "At", func() { d.At(i) })
+		mustPanic(t, "Set", func() { d.Set(i, 0) })
+		mustPanic(t, "Swap", func() { d.Swap(0, i) })
+		mustPanic(t, "PtrAt", func() { d.PtrAt(i) })
+	}
+
+	x := model[150]
+	if got := d.IndexFunc(func(v int) bool { return v == x }); got != 150 {
+		t.Fatalf("IndexFunc() = %d, want 150", got)
+	}
+	if d.ContainsFunc(func(v int) bool { return v < 0 }) {
+		t.Fatal("ContainsFunc() = true, want false")
+	}
+	buf := make([]int, 50)
+	if n := d.CopySlice(120, buf); n != 50 || !slices.Equal(buf, model[120:170]) {
+		t.Fatalf("CopySlice(120) = %d, %v, want 50, %v", n, buf, model[120:170])
+	}
+	if n := d.CopySlice(len(model)-10, buf); n != 10 || !slices.Equal(buf[:n], model[len(model)-10:]) {
+		t.Fatalf("CopySlice(len-10) = %d, %v", n, buf[:n])
+	}
+	if got := d.MakeSliceIndexCopy(100, 300); !slices.Equal(got, model[100:300]) {
+		t.Fatalf("MakeSliceIndexCopy(100, 300) = %v", got)
+	}
+}
+
+func testDrop(t *testing.T, factory func() deque.Interface[int]) {
+	drops := []struct {
+		name  string
+		drop  func(deque.Interface[int], int)
+		front bool
+	}{
+		{"DropFront", deque.Interface[int].DropFront, true},
+		{"DropFrontZero", deque.Interface[int].DropFrontZero, true},
+		{"DropBack", deque.Interface[int].DropBack, false},
+		{"DropBackZero", deque.Interface[int].DropBackZero, false},
+	}
+	for _, dr := range drops {
+		for _, n := range slices.Concat(sizes, []int{-1, 1000}) {
+			d := factory()
+			d.PushFront(-1, -2, -3)
+			model := fill(d, []int{-3, -2, -1}, 0, 200)
+			dr.drop(d, n)
+			if n >= 0 {
+				k := min(n, len(model))
+				if dr.front {
+					model = model[k:]
+				} else {
+					model = model[:len(model)-k]
+				}
+			}
+			check(t, d, model)
+			// The Deque must still work after dropping.
+			d.PushFront(7)
+			d.PushBack(8)
+			model = append(append([]int{7}, model...), 8)
+			check(t, d, model)
+		}
+	}
+
+	d := factory()
+	model := fill(d, nil, 0, 300)
+	d.ClearLazy()
+	check(t, d, nil)
+	model = fill(d, model[:0], 0, 300)
+	check(t, d, model)
+}
+
+func testResize(t *testing.T, d deque.Interface[int]) {
+	for i := range 100 {
+		d.PushFront(i)
+	}
+	var model []int
+	for i := range 100 {
+		model = append(model, 99-i)
+	}
+	model = fill(d, model, 100, 100)
+
+	if err := d.Resize(-1); !errors.Is(err, deque.ErrNegativeCapacity) {
+		t.Fatalf("Resize(-1) = %v, want %v", err, deque.ErrNegativeCapacity)
+	}
+	if err := d.Resize(len(model) / 2); !errors.Is(err, deque.ErrNotEnoughCapacity) {
+		t.Fatalf("Resize(len/2) = %v, want %v", err, deque.ErrNotEnoughCapacity)
+	}
+	check(t, d, model)
+	for _, c := range []int{1000, 1000, len(model), 500} {
+		err := d.Resize(c)
+		if err != nil && !errors.Is(err, deque.ErrSameCapacity) {
+			t.Fatalf("Resize(%d) = %v", c, err)
+		}
+		if d.Cap() < c {
+			t.Fatalf("Cap() = %d after Resize(%d)", d.Cap(), c)
+		}
+		check(t, d, model)
+	}
+	if err := d.Resize(d.Cap()); !errors.Is(err, deque.ErrSameCapacity) {
+		t.Fatalf("Resize(Cap()) = %v, want %v", err, deque.ErrSameCapacity)
+	}
+
+	if err := d.Reserve(-1); !errors.Is(err, deque.ErrNegativeCapacity) {
+		t.Fatalf("Reserve(-1) = %v, want %v", err, deque.ErrNegativeCapacity)
+	}
+	for _, n := range sizes {
+		if err := d.Reserve(n); err != nil {
+			t.Fatalf("Reserve(%d) = %v", n, err)
+		}
+		if d.Cap()-d.Len() < n {
+			t.Fatalf("Cap() = %d and Len() = %d after Reserve(%d)", d.Cap(), d.Len(), n)
+		}
+		check(t, d, model)
+	}
+
+	if c := d.Shrink(); int(c) != d.Cap() || c < uint(d.Len()) {
+		t.Fatalf("Shrink() = %d with Cap() = %d and Len() = %d", c, d.Cap(), d.Len())
+	}
+	check(t, d, model)
+	model = fill(d, model, 200, 300)
+	check(t, d, model)
+}
+
+func testIterators(t *testing.T, factory func() deque.Interface[int]) {
+	for _, n := range sizes {
+		d, model := wrapped(factory(), n)
+
+		var got []int
+		for i, v := range d.All() {
+			if i != len(got) {
+				t.Fatalf("All() yielded index %d at position %d", i, len(got))
+			}
+			got = append(got, v)
+		}
+		if !slices.Equal(got, model) {
+			t.Fatalf("All() = %v, want %v", got, model)
+		}
+
+		got = got[:0]
+		for i, v := range d.Backward() {
+			if i != len(model)-1-len(got) {
+				t.Fatalf("Backward() yielded index %d at position %d", i, len(got))
+			}
+			got = append(got, v)
+		}
+		slices.Reverse(got)
+		if !slices.Equal(got, model) {
+			t.Fatalf("Backward() = %v, want reversed %v", got, model)
+		}
+
+		if got := slices.Collect(d.Iter()); !slices.Equal(got, model) {
+			t.Fatalf("Iter() = %v, want %v", got, model)
+		}
+		got = slices.Collect(d.RIter())
+		slices.Reverse(got)
+		if !slices.Equal(got, model) {
+			t.Fatalf("RIter() = %v, want reversed %v", got, model)
+		}
+
+		got = got[:0]
+		d.ForEach(func(v int) bool {
+			got = append(got, v)
+			return len(got) < 10
+		})
+		if want := model[:min(10, len(model))]; !slices.Equal(got, want) {
+			t.Fatalf("ForEach() stopping at 10 = %v, want %v", got, want)
+		}
+
+		for i := range d.All() {
+			d.Set(i, -i)
+			model[i] = -i
+		}
+		check(t, d, model)
+
+		if n > 0 {
+			mustPanic(t, "push during Iter", func() {
+				for range d.Iter() {
+					d.PushBack(0)
+				}
+			})
+			mustPanic(t, "pop during Backward", func() {
+				for range d.Backward() {
+					d.PopFront()
+				}
+			})
+		}
+	}
+}
+
+func testIterPop(t *testing.T, factory func() deque.Interface[int]) {
+	iters := []struct {
+		name string
+		seq  func(deque.Interface[int]) iter.Seq[int]
+		back bool
+	}{
+		{"IterPopFront", deque.Interface[int].IterPopFront, false},
+		{"IterPopFrontZero", deque.Interface[int].IterPopFrontZero, false},
+		{"IterPopBack", deque.Interface[int].IterPopBack, true},
+		{"IterPopBackZero", deque.Interface[int].IterPopBackZero, true},
+	}
+	for _, it := range iters {
+		d, model := wrapped(factory(), 200)
+		popped := 0
+		for v := range it.seq(d) {
+			var want int
+			if it.back {
+				want, model = model[len(model)-1], model[:len(model)-1]
+			} else {
+				want, model = model[0], model[1:]
+			}
+			if v != want {
+				t.Fatalf("%s() yielded %d, want %d", it.name, v, want)
+			}
+			// Pushing from the loop body is allowed, as in a graph traversal.
+			if popped++; popped == 10 {
+				d.PushBack(-1)
+				model = append(model, -1)
+			}
+			if popped == 100 {
+				break
+			}
+		}
+		check(t, d, model)
+		for range it.seq(d) {
+		}
+		check(t, d, nil)
+	}
+}
+
+func testWraparound(t *testing.T, factory func() deque.Interface[int]) {
+	for _, n := range sizes {
+		d, model := wrapped(factory(), n)
+		// Cycle every element through the queue several times, so the front
+		// and back cross every boundary of the underlying storage.
+		for i := range 3*n + 300 {
+			d.PushBack(i)
+			model = append(model, i)
+			v, _ := d.PopFront()
+			if v != model[0] {
+				t.Fatalf("PopFront() = %d, want %d", v, model[0])
+			}
+			model = model[1:]
+		}
+		check(t, d, model)
+		for i := range 3*n + 300 {
+			d.PushFront(i)
+			model = append([]int{i}, model...)
+			v, _ := d.PopBack()
+			if v != model[len(model)-1] {
+				t.Fatalf("PopBack() = %d, want %d", v, model[len(model)-1])
+			}
+			model = model[:len(model)-1]
+		}
+		check(t, d, model)
+	}
+}
+
+func testRandom(t *testing.T, d deque.Interface[int]) {
+	r := rand.New(rand.NewPCG(1, 2))
+	var model []int
+	next := 0
+	for range 5000 {
+		switch r.IntN(10) {
+		case 0, 1:
+			k := r.IntN(40)
+			for range k {
+				d.PushBack(next)
+				model = append(model, next)
+				next++
+			}
+		case 2, 3:
+			k := r.IntN(40)
+			for range k {
+				d.PushFront(next)
+				model = append([]int{next}, model...)
+				next++
+			}
+		case 4:
+			if v, ok := d.PopBackZero(); ok {
+				if v != model[len(model)-1] {
+					t.Fatalf("PopBackZero() = %d, want %d", v, model[len(model)-1])
+				}
+				model = model[:len(model)-1]
+			}
+		case 5:
+			if v, ok := d.PopFrontZero(); ok {
+				if v != model[0] {
+					t.Fatalf("PopFrontZero() = %d, want %d", v, model[0])
+				}
+				model = model[1:]
+			}
+		case 6:
+			k := r.IntN(30)
+			d.DropFront(k)
+			model = model[min(k, len(model)):]
+		case 7:
+			k := r.IntN(30)
+			d.DropBackZero(k)
+			model = model[:len(model)-min(k, len(model))]
+		case 8:
+			if len(model) > 0 {
+				i := r.IntN(len(model))
+				d.Set(i, next)
+				model[i] = next
+				next++
+			}
+		case 9:
+			_ = d.Resize(len(model) + r.IntN(100))
+		}
+		check(t, d, model)
+	}
+}
+
+// wrapped returns d holding n elements that straddle the end of its
+// underlying storage, and the matching model.
+func wrapped(d deque.Interface[int], n int) (deque.Interface[int], []int) {
+	model := fill(d, nil, 0, n)
+	for i := range n / 2 {
+		d.PopFront()
+		d.PushBack(n + i)
+		model = append(model[1:], n+i)
+	}
+	for i := range n / 3 {
+		d.PopBack()
+		d.PushFront(-i)
+		model = append([]int{-i}, model[:len(model)-1]...)
+	}
+	return d, model
+}
+
+func mustPanic(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("%s did not panic", name)
+		}
+	}()
+	f()
+}
@@ -0,0 +1,15 @@
+package deque
+
+// BufCap exposes the capacity of a BoundedDeque's buffer to the tests, which
+// must never change.
+func BufCap[T any](b *BoundedDeque[T]) int { return b.d.Cap() }
+
+// BlocksOK exposes whether a BlockDeque holds exactly the blocks its elements
+// need, to check that edits free the blocks they empty.
+func BlocksOK[T any](d *BlockDeque[T]) bool {
+	return d.blocks.len() == blocksFor(d.off+d.n) && d.off < blockLen
+}
+
+// Buf exposes the buffer of a Deque to the tests, to check that the slots it
+// vacates are zeroed.
+func Buf[T any](d *Deque[T]) []T { return d.buf }
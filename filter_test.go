@@ -0,0 +1,70 @@
+package deque_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// TestCompactFuncOrder checks that CompactFunc passes the current element
+// first and the previous one second, like slices.CompactFunc, with an
+// asymmetric equality function and every wrap offset, and that BlockDeque's
+// does the same.
+func TestCompactFuncOrder(t *testing.T) {
+	succ := func(a, b int) bool { return a == b+1 }
+	s := []int{1, 2, 3, 5, 4, 5, 6}
+	want := slices.CompactFunc(slices.Clone(s), succ)
+	for off := range wrapCap {
+		d := dequeAt(t, s, off)
+		if n := d.CompactFunc(succ); n != len(s)-len(want) {
+			t.Fatalf("offset %d: CompactFunc() = %d, want %d", off, n, len(s)-len(want))
+		}
+		if got := d.MakeSliceCopy(); !slices.Equal(got, want) {
+			t.Fatalf("offset %d: CompactFunc() left %v, want %v", off, got, want)
+		}
+	}
+	b := deque.MakeBlockDeque[int]()
+	b.PushBack(s...)
+	b.CompactFunc(succ)
+	if got := b.MakeSliceCopy(); !slices.Equal(got, want) {
+		t.Fatalf("BlockDeque's CompactFunc() left %v, want %v", got, want)
+	}
+}
+
+// TestFilterWrapped runs Retain, DeleteFunc, and Compact on elements that wrap
+// around the end of the buffer at every offset, and checks the result against
+// the slices package, and that every slot no longer holding an element is
+// zeroed.
+func TestFilterWrapped(t *testing.T) {
+	s := []int{1, 2, 2, 3, 4, 4, 5}
+	even := func(v int) bool { return v%2 == 0 }
+	for _, tc := range []struct {
+		name   string
+		filter func(*deque.Deque[int]) int
+		want   []int
+	}{
+		{"Retain", func(d *deque.Deque[int]) int { return d.Retain(even) }, []int{2, 2, 4, 4}},
+		{"DeleteFunc", func(d *deque.Deque[int]) int { return d.DeleteFunc(even) }, []int{1, 3, 5}},
+		{"Compact", deque.Compact[int], []int{1, 2, 3, 4, 5}},
+	} {
+		for off := range wrapCap {
+			d := dequeAt(t, s, off)
+			if n := tc.filter(d); n != len(s)-len(tc.want) {
+				t.Fatalf("offset %d: %s() = %d, want %d", off, tc.name, n, len(s)-len(tc.want))
+			}
+			if got := d.MakeSliceCopy(); d.Len() != len(tc.want) || !slices.Equal(got, tc.want) {
+				t.Fatalf("offset %d: %s() left %v with Len() %d, want %v", off, tc.name, got, d.Len(), tc.want)
+			}
+			var live int
+			for _, v := range deque.Buf(d) {
+				if v != 0 {
+					live++
+				}
+			}
+			if live != d.Len() {
+				t.Fatalf("offset %d: %s() left %d non-zero slots in %v, want %d", off, tc.name, live, deque.Buf(d), d.Len())
+			}
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package deque_test
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// Fuzz inputs are a header of two bytes followed by pairs of bytes, each an
+// opcode and its argument. The first header byte chooses how the deque is
+// created, and the second sizes it.
+const (
+	opPushBack = iota
+	opPushFront
+	opPushBackN
+	opPushFrontN
+	opPopBack
+	opPopFront
+	opPopBackZero
+	opPopFrontZero
+	opPopBackShrink
+	opPopFrontShrink
+	opDropFront
+	opDropBack
+	opDropFrontZero
+	opDropBackZero
+	opResize
+	opReserve
+	opShrink
+	opAt
+	opSet
+	opIter
+	opCount
+)
+
+// fuzzSeeds cover the wraparound of the mask and full buffers.
+var fuzzSeeds = [][]byte{
+	// Fill a buffer of 8 exactly, then grow it while it's full.
+	{0, 8,
+		opPushBackN, 7, opPushBack, 0, opIter, 0, opPushBack, 1, opIter, 0},
+	// Wrap the head below zero on an empty buffer.
+	{0, 4,
+		opPushFront, 0, opPushFront, 1, opPushBack, 2, opPushFront, 3, opIter, 0, opPushFront, 4},
+	// Cycle a small buffer so the head and tail cross the mask many times.
+	{0, 4,
+		opPushBackN, 2, opPushBack, 0, opPopFront, 0, opPushBack, 0, opPopFront, 0,
+		opPushBack, 0, opPopFront, 0, opPushBack, 0, opPopFront, 0, opPushBack, 0,
+		opPopFront, 0, opIter, 0, opPushFrontN, 3, opIter, 0, opAt, 5, opSet, 2},
+	// Shrink and resize while the elements wrap around.
+	{0, 8,
+		opPushBackN, 5, opDropFront, 4, opPushBackN, 6, opIter, 0, opResize, 9,
+		opIter, 0, opShrink, 0, opPushFrontN, 7, opPopBackShrink, 0, opPopFrontShrink, 0},
+	// Start from a slice whose length is not a power of two.
+	{1, 5,
+		opIter, 0, opPushBack, 0, opPushFront, 0, opPopBackZero, 0, opDropBackZero, 3,
+		opDropFrontZero, 9, opPopFrontZero, 0, opPopBack, 0},
+	// Start from a full slice, then reserve and drop everything.
+	{1, 16,
+		opPushFront, 0, opReserve, 20, opPushBackN, 7, opIter, 0, opDropBack, 200,
+		opDropFront, 1, opPushBack, 0},
+}
+
+func FuzzDeque(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) < 2 {
+			return
+		}
+		var d *deque.Deque[int]
+		var model []int
+		if data[0]%2 == 0 {
+			d, _ = deque.MakeDequeWithCapacity[int](int(data[1] % 64))
+		} else {
+			for i := range int(data[1] % 64) {
+				model = append(model, -i)
+			}
+			d = deque.CopySliceToDeque(model)
+		}
+		runOps(t, d, model, data[2:], func(model []int) {
+			checkOrdered(t, d, model)
+		})
+	})
+}
+
+func FuzzBlockDeque(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) < 2 {
+			return
+		}
+		d, _ := deque.MakeBlockDequeWithCapacity[int](int(data[1]) * 8)
+		var model []int
+		if data[0]%2 == 1 {
+			for i := range int(data[1]) * 2 {
+				d.PushFront(-i)
+				model = append([]int{-i}, model...)
+			}
+		}
+		runOps(t, d, model, data[2:], nil)
+	})
+}
+
+// runOps decodes ops into calls on d, applies them to the model too, and
+// compares both after every step. extra runs checks specific to d's type.
+func runOps(t *testing.T, d deque.Interface[int], model []int, ops []byte, extra func([]int)) {
+	next := 1
+	push := func(n int) []int {
+		ts := make([]int, n)
+		for i := range ts {
+			ts[i] = next
+			next++
+		}
+		return ts
+	}
+	for len(ops) >= 2 {
+		op, arg := ops[0]%opCount, ops[1]
+		ops = ops[2:]
+		switch op {
+		case opPushBack:
+			ts := push(1)
+			d.PushBack(ts...)
+			model = append(model, ts...)
+		case opPushFront:
+			ts := push(1)
+			d.PushFront(ts...)
+			model = append(ts, model...)
+		case opPushBackN:
+			ts := push(int(arg % 32))
+			d.PushBack(ts...)
+			model = append(model, ts...)
+		case opPushFrontN:
+			ts := push(int(arg % 32))
+			d.PushFront(ts...)
+			slices.Reverse(ts)
+			model = append(ts, model...)
+		case opPopBack, opPopBackZero, opPopBackShrink:
+			pop := map[byte]func() (int, bool){
+				opPopBack:       d.PopBack,
+				opPopBackZero:   d.PopBackZero,
+				opPopBackShrink: d.PopBackShrink,
+			}[op]
+			got, ok := pop()
+			if ok != (len(model) > 0) {
+				t.Fatalf("op %d: ok = %t with length %d", op, ok, len(model))
+			}
+			if ok {
+				if want := model[len(model)-1]; got != want {
+					t.Fatalf("op %d: popped %d, want %d", op, got, want)
+				}
+				model = model[:len(model)-1]
+			}
+		case opPopFront, opPopFrontZero, opPopFrontShrink:
+			pop := map[byte]func() (int, bool){
+				opPopFront:       d.PopFront,
+				opPopFrontZero:   d.PopFrontZero,
+				opPopFrontShrink: d.PopFrontShrink,
+			}[op]
+			got, ok := pop()
+			if ok != (len(model) > 0) {
+				t.Fatalf("op %d: ok = %t with length %d", op, ok, len(model))
+			}
+			if ok {
+				if want := model[0]; got != want {
+					t.Fatalf("op %d: popped %d, want %d", op, got, want)
+				}
+				model = model[1:]
+			}
+		case opDropFront, opDropFrontZero:
+			// Arguments past 200 are negative and drop nothing.
+			n := int(arg)
+			if n > 200 {
+				n = -n
+			}
+			if op == opDropFront {
+				d.DropFront(n)
+			} else {
+				d.DropFrontZero(n)
+			}
+			model = model[min(max(n, 0), len(model)):]
+		case opDropBack, opDropBackZero:
+			n := int(arg)
+			if n > 200 {
+				n = -n
+			}
+			if op == opDropBack {
+				d.DropBack(n)
+			} else {
+				d.DropBackZero(n)
+			}
+			model = model[:len(model)-min(max(n, 0), len(model))]
+		case opResize:
+			// The capacity is rounded up, so Resize may succeed below the
+			// length, as long as the rounded capacity holds every element.
+			c := int(arg)
+			switch err := d.Resize(c); {
+			case err == nil:
+				if d.Cap() < c {
+					t.Fatalf("Cap() = %d after Resize(%d)", d.Cap(), c)
+				}
+			case errors.Is(err, deque.ErrNotEnoughCapacity):
+				if c >= len(model) {
+					t.Fatalf("Resize(%d) with length %d = %v", c, len(model), err)
+				}
+			case !errors.Is(err, deque.ErrSameCapacity):
+				t.Fatalf("Resize(%d) with length %d = %v", c, len(model), err)
+			}
+		case opReserve:
+			n := int(arg)
+			if err := d.Reserve(n); err != nil {
+				t.Fatalf("Reserve(%d) = %v", n, err)
+			}
+			if d.Cap()-d.Len() < n {
+				t.Fatalf("Cap() = %d and Len() = %d after Reserve(%d)", d.Cap(), d.Len(), n)
+			}
+		case opShrink:
+			if c := d.Shrink(); int(c) != d.Cap() {
+				t.Fatalf("Shrink() = %d, but Cap() = %d", c, d.Cap())
+			}
+		case opAt:
+			if len(model) > 0 {
+				i := int(arg) % len(model)
+				if got := d.At(i); got != model[i] {
+					t.Fatalf("At(%d) = %d, want %d", i, got, model[i])
+				}
+			}
+		case opSet:
+			if len(model) > 0 {
+				i := int(arg) % len(model)
+				v := push(1)[0]
+				d.Set(i, v)
+				model[i] = v
+			}
+		case opIter:
+			checkIters(t, d, model)
+		}
+		checkModel(t, d, model)
+		if extra != nil {
+			extra(model)
+		}
+	}
+	checkIters(t, d, model)
+}
+
+func checkModel(t *testing.T, d deque.Interface[int], model []int) {
+	t.Helper()
+	if d.Len() != len(model) {
+		t.Fatalf("Len() = %d, want %d", d.Len(), len(model))
+	}
+	if d.Cap() < d.Len() {
+		t.Fatalf("Cap() = %d is less than Len() = %d", d.Cap(), d.Len())
+	}
+	if got := d.MakeSliceCopy(); !slices.Equal(got, model) {
+		t.Fatalf("elements = %v, want %v", got, model)
+	}
+	if len(model) > 0 && (d.PeekFrontUnsafe() != model[0] || d.PeekBackUnsafe() != model[len(model)-1]) {
+		t.Fatalf("ends = %d, %d, want %d, %d", d.PeekFrontUnsafe(), d.PeekBackUnsafe(), model[0], model[len(model)-1])
+	}
+}
+
+func checkIters(t *testing.T, d deque.Interface[int], model []int) {
+	t.Helper()
+	n := 0
+	for i, v := range d.All() {
+		if n >= len(model) || i != n || v != model[n] {
+			t.Fatalf("All() yielded %d, %d at position %d with model %v", i, v, n, model)
+		}
+		n++
+	}
+	for i, v := range d.Backward() {
+		n--
+		if n < 0 || i != n || v != model[n] {
+			t.Fatalf("Backward() yielded %d, %d at position %d with model %v", i, v, n, model)
+		}
+	}
+	if got := slices.Collect(d.Iter()); !slices.Equal(got, model) {
+		t.Fatalf("Iter() = %v, want %v", got, model)
+	}
+	got := slices.Collect(d.RIter())
+	slices.Reverse(got)
+	if !slices.Equal(got, model) {
+		t.Fatalf("RIter() = %v, want reversed %v", got, model)
+	}
+}
+
+// checkOrdered checks the functions that only take a *Deque, with a reversed
+// comparator for the Func variants.
+func checkOrdered(t *testing.T, d *deque.Deque[int], model []int) {
+	t.Helper()
+	if len(model) == 0 {
+		_, okMax := deque.MaxOK(d)
+		_, okMin := deque.MinOK(d)
+		_, _, ok := deque.MinMax(d)
+		if okMax || okMin || ok || deque.ArgMax(d) != -1 || deque.ArgMin(d) != -1 {
+			t.Fatal("Max and Min functions found an element in an empty Deque")
+		}
+		return
+	}
+	rev := func(a, b int) int { return cmp.Compare(b, a) }
+	if got, ok := deque.MaxOK(d); !ok || got != slices.Max(model) {
+		t.Fatalf("MaxOK() = %d, %t, want %d", got, ok, slices.Max(model))
+	}
+	if got, ok := deque.MinOK(d); !ok || got != slices.Min(model) {
+		t.Fatalf("MinOK() = %d, %t, want %d", got, ok, slices.Min(model))
+	}
+	if got, want := deque.ArgMax(d), slices.Index(model, slices.Max(model)); got != want {
+		t.Fatalf("ArgMax() = %d, want %d", got, want)
+	}
+	if got, want := deque.ArgMinFunc(d, rev), slices.Index(model, slices.Max(model)); got != want {
+		t.Fatalf("ArgMinFunc() = %d, want %d", got, want)
+	}
+	if got, want := deque.ArgMin(d), slices.Index(model, slices.Min(model)); got != want {
+		t.Fatalf("ArgMin() = %d, want %d", got, want)
+	}
+	if lo, hi, ok := deque.MinMax(d); !ok || lo != slices.Min(model) || hi != slices.Max(model) {
+		t.Fatalf("MinMax() = %d, %d, %t, want %d, %d", lo, hi, ok, slices.Min(model), slices.Max(model))
+	}
+	if got, want := deque.Max(d), slices.Max(model); got != want {
+		t.Fatalf("Max() = %d, want %d", got, want)
+	}
+	if got, want := deque.Min(d), slices.Min(model); got != want {
+		t.Fatalf("Min() = %d, want %d", got, want)
+	}
+	if got, want := deque.MaxFunc(d, rev), slices.MaxFunc(model, rev); got != want {
+		t.Fatalf("MaxFunc() = %d, want %d", got, want)
+	}
+	if got, want := deque.MinFunc(d, rev), slices.MinFunc(model, rev); got != want {
+		t.Fatalf("MinFunc() = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package deque
+
+import "iter"
+
+// Queue is the method set of a FIFO queue. Deque, BlockDeque, SyncDeque, and
+// BoundedDeque implement it.
+type Queue[T any] interface {
+	Len() int
+	PushBack(ts ...T)
+	PeekFront() (T, bool)
+	PopFront() (T, bool)
+}
+
+// Stack is the method set of a LIFO stack. Deque, BlockDeque, SyncDeque, and
+// BoundedDeque implement it.
+type Stack[T any] interface {
+	Len() int
+	PushBack(ts ...T)
+	PeekBack() (T, bool)
+	PopBack() (T, bool)
+}
+
+// Sequence is the method set of an indexed sequence. Deque and BlockDeque
+// implement it.
+type Sequence[T any] interface {
+	Len() int
+	At(i int) T
+	Set(i int, t T)
+	Swap(i, j int)
+	All() iter.Seq2[int, T]
+	Backward() iter.Seq2[int, T]
+}
+
+// Interface is the method set shared by Deque and BlockDeque, so that code can
+// switch between them. The package dequetest checks implementations against
+// it.
+type Interface[T any] interface {
+	Queue[T]
+	Stack[T]
+	Sequence[T]
+
+	Empty() bool
+	Cap() int
+	PushFront(ts ...T)
+	PeekBackUnsafe() T
+	PeekFrontUnsafe() T
+
+	PopBackZero() (T, bool)
+	PopBackShrink() (T, bool)
+	PopBackUnsafe() T
+	PopBackZeroUnsafe() T
+	PopFrontZero() (T, bool)
+	PopFrontShrink() (T, bool)
+	PopFrontUnsafe() T
+	PopFrontZeroUnsafe() T
+	DropFront(n int)
+	DropFrontZero(n int)
+	DropBack(n int)
+	DropBackZero(n int)
+
+	Resize(minCapacity int) error
+	Reserve(n int) error
+	Shrink() uint
+
+	MakeSliceCopy() []T
+	MakeSliceIndexCopy(start, end int) []T
+	CopySlice(start int, buf []T) int
+	AtUnsafe(i int) T
+	SetUnsafe(i int, t T)
+	SwapUnsafe(i, j int)
+	PtrAt(i int) *T
+	FrontPtr() *T
+	BackPtr() *T
+	PushBackSlot() *T
+	PushFrontSlot() *T
+	ClearLazy()
+	ClearEager()
+	ContainsFunc(f func(T) bool) bool
+	IndexFunc(f func(T) bool) int
+
+	ForEach(f func(T) bool)
+	Iter() iter.Seq[T]
+	RIter() iter.Seq[T]
+	IterPopFront() iter.Seq[T]
+	IterPopFrontZero() iter.Seq[T]
+	IterPopBack() iter.Seq[T]
+	IterPopBackZero() iter.Seq[T]
+}
+
+var (
+	_ Interface[int] = (*Deque[int])(nil)
+	_ Interface[int] = (*BlockDeque[int])(nil)
+	_ Queue[int]     = (*SyncDeque[int])(nil)
+	_ Stack[int]     = (*SyncDeque[int])(nil)
+	_ Queue[int]     = (*BoundedDeque[int])(nil)
+	_ Stack[int]     = (*BoundedDeque[int])(nil)
+)
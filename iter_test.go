@@ -0,0 +1,129 @@
+package deque_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// TestAllWrapped checks that All and Backward yield each element with its own
+// index when the elements wrap around the end of the buffer.
+func TestAllWrapped(t *testing.T) {
+	d, _ := deque.MakeDequeWithCapacity[int](8)
+	d.PushBack(0, 0, 0, 0, 0, 0)
+	d.DropFront(5)
+	d.PushBack(1, 2, 3, 4, 5)
+	d.SetUnsafe(0, 0)
+
+	var n int
+	for i, v := range d.All() {
+		if i != n || v != i {
+			t.Fatalf("All() yielded %d, %d at position %d", i, v, n)
+		}
+		n++
+	}
+	if n != d.Len() {
+		t.Fatalf("All() yielded %d elements, want %d", n, d.Len())
+	}
+	n = d.Len()
+	for i, v := range d.Backward() {
+		n--
+		if i != n || v != i {
+			t.Fatalf("Backward() yielded %d, %d at position %d", i, v, n)
+		}
+	}
+}
+
+// mustPanic fails the test unless f panics.
+func mustPanic(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("%s did not panic", name)
+		}
+	}()
+	f()
+}
+
+// TestIteratorRemoveWrapped removes elements with an Iterator at every wrap
+// offset, so removals shift elements across the end of the buffer from either
+// side.
+func TestIteratorRemoveWrapped(t *testing.T) {
+	s := []int{0, 1, 2, 3, 4, 5, 6}
+	for off := range wrapCap {
+		d := dequeAt(t, s, off)
+		var seen []int
+		for it := d.Iterator(); it.Next(); {
+			seen = append(seen, it.Value())
+			switch v := it.Value(); {
+			case v%3 == 0:
+				it.Remove()
+				mustPanic(t, "Value after Remove", func() { it.Value() })
+			case v == 4:
+				it.Set(40)
+			}
+		}
+		if !slices.Equal(seen, s) {
+			t.Fatalf("offset %d: Iterator visited %v, want %v", off, seen, s)
+		}
+		if got, want := d.MakeSliceCopy(), []int{1, 2, 40, 5}; !slices.Equal(got, want) {
+			t.Fatalf("offset %d: Iterator left %v, want %v", off, got, want)
+		}
+	}
+}
+
+// TestIteratorsFailFast checks that every iterator panics when the loop body
+// structurally modifies the Deque, but not when it overwrites elements.
+func TestIteratorsFailFast(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		loop func(d *deque.Deque[int], body func())
+	}{
+		{"All", func(d *deque.Deque[int], body func()) {
+			for range d.All() {
+				body()
+			}
+		}},
+		{"Iter", func(d *deque.Deque[int], body func()) {
+			for range d.Iter() {
+				body()
+			}
+		}},
+		{"Backward", func(d *deque.Deque[int], body func()) {
+			for range d.Backward() {
+				body()
+			}
+		}},
+		{"RIter", func(d *deque.Deque[int], body func()) {
+			for range d.RIter() {
+				body()
+			}
+		}},
+		{"ForEach", func(d *deque.Deque[int], body func()) {
+			d.ForEach(func(int) bool {
+				body()
+				return true
+			})
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := dequeAt(t, []int{0, 1, 2, 3, 4}, 6)
+			tc.loop(d, func() { d.Set(0, 9) })
+			for _, mutate := range []struct {
+				name string
+				f    func(*deque.Deque[int])
+			}{
+				{"PushBack", func(d *deque.Deque[int]) { d.PushBack(5) }},
+				{"PopFront", func(d *deque.Deque[int]) { d.PopFront() }},
+				{"Insert", func(d *deque.Deque[int]) { d.Insert(1, 5) }},
+				{"ClearLazy", (*deque.Deque[int]).ClearLazy},
+			} {
+				d := dequeAt(t, []int{0, 1, 2, 3, 4}, 6)
+				mustPanic(t, tc.name+" with "+mutate.name, func() {
+					tc.loop(d, func() { mutate.f(d) })
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package deque
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalJSON encodes the Deque as a JSON array of its elements from front to
+// back. A nil Deque is encoded as null.
+func (d *Deque[T]) MarshalJSON() ([]byte, error) {
+	if d == nil {
+		return []byte("null"), nil
+	}
+	buf := []byte{'['}
+	s1, s2 := d.slices()
+	for _, s := range [2][]T{s1, s2} {
+		for i := range s {
+			// Marshal a pointer, so methods with pointer receivers are used,
+			// as they are for slices.
+			b, err := json.Marshal(&s[i])
+			if err != nil {
+				return nil, err
+			}
+			if len(buf) > 1 {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, b...)
+		}
+	}
+	return append(buf, ']'), nil
+}
+
+// UnmarshalJSON replaces the elements of the Deque with those of a JSON array,
+// from front to back. The array is counted first, so the buffer is allocated
+// once, rounded up to a power of two, and elements are decoded straight into
+// it. An empty array empties the Deque, while null leaves it unchanged. If
+// decoding fails, the Deque is left unchanged too.
+//
+// If the Deque has a maximum length, only the last elements of the array are
+// kept, and the others are evicted from the front as they're decoded. The
+// OnEvict function is only called once decoding succeeds, with every evicted
+// element in order, so those elements are held until then.
+//
+// json.Unmarshal allocates a Deque for a nil pointer, so Deques may be used as
+// fields of structs that are unmarshaled.
+func (d *Deque[T]) UnmarshalJSON(data []byte) error {
+	return d.decodeJSON(json.NewDecoder(bytes.NewReader(data)), countJSON(data))
+}
+
+// DecodeJSON reads the next JSON value from dec, which must be an array or
+// null, and replaces the elements of the Deque like UnmarshalJSON. Elements are
+// decoded one at a time as they're read, so a huge array never needs to be
+// buffered in full. Since its length is unknown, the buffer grows as in
+// PushBack.
+func (d *Deque[T]) DecodeJSON(dec *json.Decoder) error {
+	return d.decodeJSON(dec, 0)
+}
+
+// Internal implementation for UnmarshalJSON and DecodeJSON. n is the expected
+// number of elements, used to size the buffer.
+func (d *Deque[T]) decodeJSON(dec *json.Decoder, n uint) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case nil:
+		return nil
+	case json.Delim('['):
+	default:
+		return fmt.Errorf("deque: cannot unmarshal JSON %v into a Deque", tok)
+	}
+
+	if d.maxLen != 0 {
+		n = min(n, d.maxLen)
+	}
+	c := ceilPow2(n)
+	// Decode into a new Deque, so d is unchanged if decoding fails.
+	nd := Deque[T]{buf: make([]T, c), mask: c - 1, maxLen: d.maxLen}
+	// Hold evicted elements until decoding succeeds, to call OnEvict.
+	var evicted []T
+	var zero T
+	for dec.More() {
+		switch {
+		case nd.maxLen != 0 && nd.len() >= nd.maxLen:
+			t := nd.PopFrontUnsafe()
+			if d.onEvict != nil {
+				evicted = append(evicted, t)
+			}
+		case nd.Full():
+			nd.resize(nd.cap() << 1)
+		}
+		// Decoding merges into existing values, so start from a zero value.
+		p := &nd.buf[nd.tail&nd.mask]
+		*p = zero
+		if err := dec.Decode(p); err != nil {
+			return err
+		}
+		nd.tail++
+	}
+	// Consume the closing bracket.
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	d.buf, d.head, d.tail, d.mask = nd.buf, nd.head, nd.tail, nd.mask
+	d.mod++
+	for _, t := range evicted {
+		d.evict(t)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the BlockDeque as a JSON array of its elements from front
+// to back, like Deque's MarshalJSON. A nil BlockDeque is encoded as null.
+func (d *BlockDeque[T]) MarshalJSON() ([]byte, error) {
+	if d == nil {
+		return []byte("null"), nil
+	}
+	buf := []byte{'['}
+	for i := uint(0); i < d.n; {
+		s := d.span(i)
+		for j := range s {
+			b, err := json.Marshal(&s[j])
+			if err != nil {
+				return nil, err
+			}
+			if len(buf) > 1 {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, b...)
+		}
+		i += uint(len(s))
+	}
+	return append(buf, ']'), nil
+}
+
+// UnmarshalJSON replaces the elements of the BlockDeque with those of a JSON
+// array, from front to back, with the same semantics as Deque's
+// UnmarshalJSON. Since blocks are allocated as elements are decoded, the array
+// is not counted first.
+func (d *BlockDeque[T]) UnmarshalJSON(data []byte) error {
+	return d.DecodeJSON(json.NewDecoder(bytes.NewReader(data)))
+}
+
+// DecodeJSON reads the next JSON value from dec, which must be an array or
+// null, and replaces the elements of the BlockDeque like UnmarshalJSON.
+// Elements are decoded one at a time as they're read, so a huge array never
+// needs to be buffered in full.
+func (d *BlockDeque[T]) DecodeJSON(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case nil:
+		return nil
+	case json.Delim('['):
+	default:
+		return fmt.Errorf("deque: cannot unmarshal JSON %v into a BlockDeque", tok)
+	}
+
+	// Decode into a new BlockDeque, so d is unchanged if decoding fails.
+	nd := BlockDeque[T]{blocks: MakeDeque[*[blockLen]T](), maxLen: d.maxLen}
+	// Hold evicted elements until decoding succeeds, to call OnEvict.
+	var evicted []T
+	var zero T
+	for dec.More() {
+		if nd.maxLen != 0 && nd.n >= nd.maxLen {
+			t := nd.PopFrontZeroUnsafe()
+			if d.onEvict != nil {
+				evicted = append(evicted, t)
+			}
+		}
+		// Decoding merges into existing values, so start from a zero value.
+		p := nd.pushBack()
+		*p = zero
+		if err := dec.Decode(p); err != nil {
+			return err
+		}
+	}
+	// Consume the closing bracket.
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	d.blocks, d.free, d.off, d.n = nd.blocks, nd.free, nd.off, nd.n
+	d.mod++
+	for _, t := range evicted {
+		d.evict(t)
+	}
+	return nil
+}
+
+// countJSON counts the elements of the JSON array in data by scanning its
+// top-level commas, without validating it. It returns 0 if data is not an
+// array.
+func countJSON(data []byte) uint {
+	var n, depth uint
+	inString, escaped, empty := false, false, true
+	for _, c := range data {
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case c == '[' || c == '{':
+			if depth == 1 {
+				empty = false
+			}
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case depth == 1:
+			empty = false
+			if c == ',' {
+				n++
+			} else if c == '"' {
+				inString = true
+			}
+		case c == '"':
+			inString = true
+		}
+	}
+	if empty {
+		return 0
+	}
+	return n + 1
+}
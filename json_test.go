@@ -0,0 +1,153 @@
+package deque_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	d, _ := deque.MakeDequeWithCapacity[string](4)
+	d.PushBack("a", "b", "c")
+	d.DropFront(2)
+	// The elements wrap around the end of the buffer.
+	d.PushBack(`"d"`, "[e,]")
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `["c","\"d\"","[e,]"]`; string(data) != want {
+		t.Fatalf("Marshal() = %s, want %s", data, want)
+	}
+
+	var got struct{ Q *deque.Deque[string] }
+	if err := json.Unmarshal([]byte(`{"Q":`+string(data)+`}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !deque.Equal(got.Q, d) || got.Q.Cap() != 4 {
+		t.Fatalf("Unmarshal() = %v with capacity %d, want %v", got.Q.MakeSliceCopy(), got.Q.Cap(), d.MakeSliceCopy())
+	}
+}
+
+func TestJSONNullAndEmpty(t *testing.T) {
+	var nilDeque *deque.Deque[int]
+	if data, _ := json.Marshal(nilDeque); string(data) != "null" {
+		t.Fatalf("Marshal(nil) = %s, want null", data)
+	}
+	d := deque.CopySliceToDeque([]int{1, 2})
+	if err := json.Unmarshal([]byte("null"), d); err != nil || d.Len() != 2 {
+		t.Fatalf("Unmarshal(null) = %v with length %d, want no change", err, d.Len())
+	}
+	if err := json.Unmarshal([]byte(" [ ] "), d); err != nil || d.Len() != 0 {
+		t.Fatalf("Unmarshal([]) = %v with length %d, want empty", err, d.Len())
+	}
+	if data, _ := json.Marshal(d); string(data) != "[]" {
+		t.Fatalf("Marshal(empty) = %s, want []", data)
+	}
+	if err := json.Unmarshal([]byte(`[1,"x"]`), d); err == nil || d.Len() != 0 {
+		t.Fatalf("Unmarshal(invalid) = %v with length %d, want an error and no change", err, d.Len())
+	}
+	if err := json.Unmarshal([]byte(`{}`), d); err == nil {
+		t.Fatal("Unmarshal({}) succeeded")
+	}
+}
+
+func TestJSONRing(t *testing.T) {
+	d, _ := deque.MakeRing[[]int](2)
+	var evicted [][]int
+	d.OnEvict(func(s []int) { evicted = append(evicted, s) })
+	if err := json.Unmarshal([]byte(`[[1],[2,3],[],[4]]`), d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Len() != 2 || d.Cap() != 2 || !slices.Equal(d.At(0), []int{}) || !slices.Equal(d.At(1), []int{4}) {
+		t.Fatalf("Unmarshal() = %v with capacity %d", d.MakeSliceCopy(), d.Cap())
+	}
+	if len(evicted) != 2 || !slices.Equal(evicted[1], []int{2, 3}) {
+		t.Fatalf("evicted %v, want [[1] [2 3]]", evicted)
+	}
+
+	// Nothing is evicted if decoding fails.
+	evicted = nil
+	if err := json.Unmarshal([]byte(`[[5],[6],[7],"x"]`), d); err == nil {
+		t.Fatal("Unmarshal(invalid) succeeded")
+	}
+	if len(evicted) != 0 || !slices.Equal(d.At(1), []int{4}) {
+		t.Fatalf("Unmarshal(invalid) evicted %v and left %v, want no change", evicted, d.MakeSliceCopy())
+	}
+}
+
+// celsius has a MarshalJSON method with a pointer receiver.
+type celsius float64
+
+func (c *celsius) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%g°C", float64(*c)))
+}
+
+func TestJSONPointerMarshaler(t *testing.T) {
+	s := []celsius{20, 21.5}
+	want, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(deque.CopySliceToDeque(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(want) {
+		t.Fatalf("Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`[1, 2, 3] null [4]`))
+	d := deque.MakeDeque[int]()
+	for _, want := range [][]int{{1, 2, 3}, {1, 2, 3}, {4}} {
+		if err := d.DecodeJSON(dec); err != nil {
+			t.Fatal(err)
+		}
+		if !deque.EqualSlice(d, want) {
+			t.Fatalf("DecodeJSON() = %v, want %v", d.MakeSliceCopy(), want)
+		}
+	}
+}
+
+func TestBlockDequeJSON(t *testing.T) {
+	b := deque.MakeBlockDeque[celsius]()
+	for i := range 300 {
+		b.PushFront(celsius(i))
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := json.Marshal(b.MakeSliceCopy())
+	if string(data) != string(want) {
+		t.Fatalf("Marshal() = %.40s..., want %.40s...", data, want)
+	}
+
+	var got struct{ B *deque.BlockDeque[int] }
+	if err := json.Unmarshal([]byte(`{"B":[1,2,3]}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if s := got.B.MakeSliceCopy(); !slices.Equal(s, []int{1, 2, 3}) {
+		t.Fatalf("Unmarshal() = %v, want [1 2 3]", s)
+	}
+
+	r := deque.MakeBlockDeque[int]()
+	_ = r.SetMaxLen(2)
+	var evicted []int
+	r.OnEvict(func(v int) { evicted = append(evicted, v) })
+	if err := json.Unmarshal([]byte(`[1,2,3,"x"]`), r); err == nil || r.Len() != 0 || evicted != nil {
+		t.Fatalf("Unmarshal(invalid) = %v with length %d and evicted %v, want no change", err, r.Len(), evicted)
+	}
+	if err := json.Unmarshal([]byte(`[1,2,3,4]`), r); err != nil {
+		t.Fatal(err)
+	}
+	if s := r.MakeSliceCopy(); !slices.Equal(s, []int{3, 4}) || !slices.Equal(evicted, []int{1, 2}) {
+		t.Fatalf("Unmarshal() = %v and evicted %v, want [3 4] and [1 2]", s, evicted)
+	}
+}
@@ -0,0 +1,42 @@
+package deque_test
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+)
+
+type order struct {
+	id    int
+	price int
+}
+
+// TestMinMaxFuncStructs checks that the comparator decides across the
+// wraparound, and that ties keep the first element.
+func TestMinMaxFuncStructs(t *testing.T) {
+	byPrice := func(a, b order) int { return cmp.Compare(a.price, b.price) }
+	d, _ := deque.MakeDequeWithCapacity[order](8)
+	d.PushBack(make([]order, 6)...)
+	d.DropFront(6)
+	// The last two orders wrap around to the start of the buffer.
+	d.PushBack(order{1, 5}, order{2, 1}, order{3, 9}, order{4, 1})
+	d.PushFront(order{5, 9})
+
+	if got := deque.MaxFunc(d, byPrice); got.id != 5 {
+		t.Fatalf("MaxFunc() = %v, want id 5", got)
+	}
+	if got := deque.MinFunc(d, byPrice); got.id != 2 {
+		t.Fatalf("MinFunc() = %v, want id 2", got)
+	}
+	if got := deque.ArgMaxFunc(d, byPrice); got != 0 {
+		t.Fatalf("ArgMaxFunc() = %d, want 0", got)
+	}
+	if got := deque.ArgMinFunc(d, byPrice); got != 2 {
+		t.Fatalf("ArgMinFunc() = %d, want 2", got)
+	}
+	lo, hi, ok := deque.MinMaxFunc(d, byPrice)
+	if !ok || lo.id != 2 || hi.id != 5 {
+		t.Fatalf("MinMaxFunc() = %v, %v, %t, want ids 2 and 5", lo, hi, ok)
+	}
+}
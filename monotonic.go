@@ -0,0 +1,99 @@
+package deque
+
+import "cmp"
+
+// MonotonicDeque tracks the maximum and minimum of a sliding window in O(1)
+// amortized time per operation. Elements are pushed at the back of the window
+// and expire from its front, either one at a time with PopFront or by index
+// with ExpireBefore.
+//
+// Internally, it keeps two Deques of candidates: one with decreasing values
+// for the maximum, and one with increasing values for the minimum. Elements
+// that can never become an extreme again are discarded as soon as a newer,
+// more extreme element is pushed, so the window's elements are not stored.
+//
+// To create a MonotonicDeque instance, you must use one of the available
+// constructors, MakeMonotonicDeque(cmp) or MakeOrderedMonotonicDeque().
+type MonotonicDeque[T any] struct {
+	cmp        func(T, T) int
+	maxs, mins *Deque[indexed[T]]
+	head, tail int
+}
+
+// indexed is an element tagged with its position in the stream.
+type indexed[T any] struct {
+	i int
+	t T
+}
+
+// MakeMonotonicDeque takes in a comparison function with the same semantics
+// as the one in slices.SortFunc.
+func MakeMonotonicDeque[T any](cmp func(T, T) int) *MonotonicDeque[T] {
+	return &MonotonicDeque[T]{
+		cmp:  cmp,
+		maxs: MakeDeque[indexed[T]](),
+		mins: MakeDeque[indexed[T]](),
+	}
+}
+
+// MakeOrderedMonotonicDeque creates a MonotonicDeque for ordered types, which
+// compares elements with cmp.Compare.
+func MakeOrderedMonotonicDeque[T cmp.Ordered]() *MonotonicDeque[T] {
+	return MakeMonotonicDeque(cmp.Compare[T])
+}
+
+// Len returns the number of elements in the window.
+func (m *MonotonicDeque[T]) Len() int { return m.tail - m.head }
+
+// Push adds t to the back of the window and returns its index. Indexes start
+// at 0 and increase by one with every push, so they can be passed to
+// ExpireBefore.
+func (m *MonotonicDeque[T]) Push(t T) int {
+	for !m.maxs.Empty() && m.cmp(m.maxs.PeekBackUnsafe().t, t) <= 0 {
+		m.maxs.PopBackZeroUnsafe()
+	}
+	for !m.mins.Empty() && m.cmp(m.mins.PeekBackUnsafe().t, t) >= 0 {
+		m.mins.PopBackZeroUnsafe()
+	}
+	e := indexed[T]{i: m.tail, t: t}
+	m.maxs.PushBack(e)
+	m.mins.PushBack(e)
+	m.tail++
+	return e.i
+}
+
+// PopFront removes the oldest element from the window. If the window is empty,
+// returns false.
+func (m *MonotonicDeque[T]) PopFront() bool {
+	if m.head == m.tail {
+		return false
+	}
+	m.ExpireBefore(m.head + 1)
+	return true
+}
+
+// ExpireBefore removes every element whose index is less than i from the
+// window. Indexes that already expired or were never pushed are ignored.
+func (m *MonotonicDeque[T]) ExpireBefore(i int) {
+	m.head = min(max(m.head, i), m.tail)
+	for !m.maxs.Empty() && m.maxs.PeekFrontUnsafe().i < m.head {
+		m.maxs.PopFrontZeroUnsafe()
+	}
+	for !m.mins.Empty() && m.mins.PeekFrontUnsafe().i < m.head {
+		m.mins.PopFrontZeroUnsafe()
+	}
+}
+
+// Max returns the maximum element in the window. If there are several, it
+// returns the newest. If the window is empty, it returns false.
+func (m *MonotonicDeque[T]) Max() (t T, ok bool) {
+	e, ok := m.maxs.PeekFront()
+	return e.t, ok
+}
+
+// Min returns the minimum element in the window. If there are several, it
+// returns the newest. If the window is empty, it returns false.
+func (m *MonotonicDeque[T]) Min() (t T, ok bool) {
+	e, ok := m.mins.PeekFront()
+	return e.t, ok
+}
@@ -0,0 +1,94 @@
+package deque_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// TestMonotonicDequeWindow pushes a stream with many equal keys and expires
+// it one element at a time and by index, checking Max and Min against a scan
+// of the window after every step. Ties are told apart by Seq, since the
+// newest of several extremes must be returned.
+func TestMonotonicDequeWindow(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	m := deque.MakeMonotonicDeque(cmpKey)
+	var stream []pair
+	head := 0
+	for step := range 5000 {
+		switch op := r.IntN(10); {
+		case op < 6:
+			p := pair{r.IntN(5), len(stream)}
+			if i := m.Push(p); i != len(stream) {
+				t.Fatalf("step %d: Push() = %d, want %d", step, i, len(stream))
+			}
+			stream = append(stream, p)
+		case op < 8:
+			if got, want := m.PopFront(), head < len(stream); got != want {
+				t.Fatalf("step %d: PopFront() = %t, want %t", step, got, want)
+			}
+			head = min(head+1, len(stream))
+		default:
+			i := head + r.IntN(8) - 2
+			m.ExpireBefore(i)
+			head = min(max(head, i), len(stream))
+		}
+
+		window := stream[head:]
+		if m.Len() != len(window) {
+			t.Fatalf("step %d: Len() = %d, want %d", step, m.Len(), len(window))
+		}
+		var wantMax, wantMin pair
+		for i, p := range window {
+			if i == 0 || p.Key >= wantMax.Key {
+				wantMax = p
+			}
+			if i == 0 || p.Key <= wantMin.Key {
+				wantMin = p
+			}
+		}
+		gotMax, okMax := m.Max()
+		gotMin, okMin := m.Min()
+		if okMax != (len(window) > 0) || okMin != (len(window) > 0) {
+			t.Fatalf("step %d: Max() and Min() = %t and %t with %d elements", step, okMax, okMin, len(window))
+		}
+		if gotMax != wantMax || gotMin != wantMin {
+			t.Fatalf("step %d: Max() = %v and Min() = %v, want %v and %v", step, gotMax, gotMin, wantMax, wantMin)
+		}
+	}
+}
+
+// TestMonotonicDequeEmpty checks an empty window, before any push and after
+// every element expired.
+func TestMonotonicDequeEmpty(t *testing.T) {
+	m := deque.MakeOrderedMonotonicDeque[int]()
+	if _, ok := m.Max(); ok {
+		t.Fatal("Max() on an empty window returned true")
+	}
+	if _, ok := m.Min(); ok {
+		t.Fatal("Min() on an empty window returned true")
+	}
+	if m.PopFront() {
+		t.Fatal("PopFront() on an empty window returned true")
+	}
+
+	m.Push(3)
+	m.Push(3)
+	m.ExpireBefore(10)
+	if m.Len() != 0 {
+		t.Fatalf("Len() = %d after expiring past the back, want 0", m.Len())
+	}
+	if _, ok := m.Max(); ok {
+		t.Fatal("Max() after every element expired returned true")
+	}
+	if _, ok := m.Min(); ok {
+		t.Fatal("Min() after every element expired returned true")
+	}
+	if i := m.Push(1); i != 2 {
+		t.Fatalf("Push() = %d after expiring past the back, want 2", i)
+	}
+	if v, ok := m.Max(); !ok || v != 1 || m.Len() != 1 {
+		t.Fatalf("Max() = %d, %t with Len() %d, want 1, true with 1", v, ok, m.Len())
+	}
+}
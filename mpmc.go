@@ -0,0 +1,177 @@
+package deque
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+)
+
+// MPMCQueue is a lock-free, fixed capacity FIFO queue for any number of
+// producer and consumer goroutines, following Dmitry Vyukov's bounded MPMC
+// queue. Every slot carries a sequence number that tells producers and
+// consumers whose turn it is, so they only contend on the head or tail
+// counter of their own side.
+//
+// To create an MPMCQueue instance, you must use MakeMPMCQueue(cap).
+type MPMCQueue[T any] struct {
+	_ cacheLinePad
+	// Consumer side.
+	head atomic.Uint64
+	_    cacheLinePad
+	// Producer side.
+	tail atomic.Uint64
+	_    cacheLinePad
+	// Read-only after construction.
+	slots    []mpmcSlot[T]
+	mask     uint64
+	notEmpty parker
+	notFull  parker
+}
+
+// A slot is ready to be written at position p when seq == p, and ready to be
+// read when seq == p+1.
+type mpmcSlot[T any] struct {
+	seq atomic.Uint64
+	t   T
+}
+
+// MakeMPMCQueue takes in the desired capacity. Note that if the supplied
+// capacity is not a power of two, it will be increased to the next power of
+// two, and that the minimum capacity is 2, since the sequence numbers of a
+// single slot can't tell a written slot from a read one. The capacity is
+// fixed: enqueueing to a full MPMCQueue fails or blocks. Returns an error if
+// passed a negative value.
+func MakeMPMCQueue[T any](capacity int) (*MPMCQueue[T], error) {
+	if capacity < 0 {
+		return nil, ErrNegativeCapacity
+	}
+	c := ceilPow2(max(2, uint(capacity)))
+	q := &MPMCQueue[T]{slots: make([]mpmcSlot[T], c), mask: uint64(c) - 1}
+	for i := range q.slots {
+		q.slots[i].seq.Store(uint64(i))
+	}
+	return q, nil
+}
+
+// Len returns an estimate of the number of elements in the MPMCQueue. By the
+// time the caller inspects the result, other goroutines may have changed it.
+func (q *MPMCQueue[T]) Len() int {
+	h := q.head.Load()
+	t := q.tail.Load()
+	if t < h {
+		return 0
+	}
+	return int(min(t-h, uint64(len(q.slots))))
+}
+
+// Cap returns the MPMCQueue capacity.
+func (q *MPMCQueue[T]) Cap() int { return len(q.slots) }
+
+// TryEnqueue puts t at the back of the MPMCQueue. If it's full, returns false
+// without blocking.
+func (q *MPMCQueue[T]) TryEnqueue(t T) bool {
+	pos := q.tail.Load()
+	for {
+		s := &q.slots[pos&q.mask]
+		seq := s.seq.Load()
+		switch diff := int64(seq - pos); {
+		case diff == 0:
+			if q.tail.CompareAndSwap(pos, pos+1) {
+				s.t = t
+				s.seq.Store(pos + 1)
+				q.notEmpty.wake()
+				return true
+			}
+			pos = q.tail.Load()
+		case diff < 0:
+			// The slot still holds an element from the previous lap.
+			return false
+		default:
+			// Another producer claimed this position first.
+			pos = q.tail.Load()
+		}
+	}
+}
+
+// TryDequeue removes the first element in the MPMCQueue, zeroes its slot, and
+// returns it. If it's empty, returns false without blocking.
+func (q *MPMCQueue[T]) TryDequeue() (t T, ok bool) {
+	pos := q.head.Load()
+	for {
+		s := &q.slots[pos&q.mask]
+		seq := s.seq.Load()
+		switch diff := int64(seq - (pos + 1)); {
+		case diff == 0:
+			if q.head.CompareAndSwap(pos, pos+1) {
+				t = s.t
+				var zero T
+				s.t = zero
+				s.seq.Store(pos + q.mask + 1)
+				q.notFull.wake()
+				return t, true
+			}
+			pos = q.head.Load()
+		case diff < 0:
+			// The slot hasn't been written in this lap yet.
+			return
+		default:
+			// Another consumer claimed this position first.
+			pos = q.head.Load()
+		}
+	}
+}
+
+// Enqueue puts t at the back of the MPMCQueue, blocking while it's full. It
+// spins for a while before parking the goroutine. It returns ctx.Err() if the
+// context is done first.
+func (q *MPMCQueue[T]) Enqueue(ctx context.Context, t T) error {
+	for i := 0; ; i++ {
+		if q.TryEnqueue(t) {
+			return nil
+		}
+		if i < spinLimit {
+			runtime.Gosched()
+			continue
+		}
+		notFull := q.notFull.prepare()
+		if q.TryEnqueue(t) {
+			q.notFull.done()
+			return nil
+		}
+		select {
+		case <-notFull:
+			q.notFull.done()
+		case <-ctx.Done():
+			q.notFull.done()
+			return ctx.Err()
+		}
+	}
+}
+
+// Dequeue removes the first element in the MPMCQueue, zeroes its slot, and
+// returns it, blocking while it's empty. It spins for a while before parking
+// the goroutine. It returns ctx.Err() if the context is done first.
+func (q *MPMCQueue[T]) Dequeue(ctx context.Context) (T, error) {
+	for i := 0; ; i++ {
+		if t, ok := q.TryDequeue(); ok {
+			return t, nil
+		}
+		if i < spinLimit {
+			runtime.Gosched()
+			continue
+		}
+		notEmpty := q.notEmpty.prepare()
+		if t, ok := q.TryDequeue(); ok {
+			q.notEmpty.done()
+			return t, nil
+		}
+		select {
+		case <-notEmpty:
+			q.notEmpty.done()
+		case <-ctx.Done():
+			q.notEmpty.done()
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package deque_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// TestMPMCQueueStress has producers and consumers share a small queue, then
+// checks every item was dequeued exactly once.
+func TestMPMCQueueStress(t *testing.T) {
+	const (
+		producers = 4
+		consumers = 4
+		perWorker = 20_000
+	)
+	q, err := deque.MakeMPMCQueue[int](8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for p := range producers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWorker {
+				if err := q.Enqueue(ctx, p*perWorker+i); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+
+	taken := make([][]int, consumers)
+	for c := range consumers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range producers * perWorker / consumers {
+				v, err := q.Dequeue(ctx)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				taken[c] = append(taken[c], v)
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := make([]int, producers*perWorker)
+	for _, vs := range taken {
+		for _, v := range vs {
+			seen[v]++
+		}
+	}
+	for v, n := range seen {
+		if n != 1 {
+			t.Fatalf("item %d dequeued %d times", v, n)
+		}
+	}
+	if _, ok := q.TryDequeue(); ok {
+		t.Fatal("TryDequeue() on empty queue returned true")
+	}
+}
+
+// TestMPMCQueueSmall checks that the smallest capacities, which are raised to
+// 2, keep FIFO order and report full and empty queues.
+func TestMPMCQueueSmall(t *testing.T) {
+	for _, capacity := range []int{0, 1, 2} {
+		q, err := deque.MakeMPMCQueue[int](capacity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.Cap() != 2 {
+			t.Fatalf("MakeMPMCQueue(%d).Cap() = %d, want 2", capacity, q.Cap())
+		}
+		for round := range 3 {
+			if _, ok := q.TryDequeue(); ok {
+				t.Fatalf("capacity %d: TryDequeue() on empty queue returned true", capacity)
+			}
+			for i := range 2 {
+				if !q.TryEnqueue(round*2 + i) {
+					t.Fatalf("capacity %d: TryEnqueue() of element %d returned false", capacity, i)
+				}
+			}
+			if q.TryEnqueue(-1) {
+				t.Fatalf("capacity %d: TryEnqueue() on full queue returned true", capacity)
+			}
+			for i := range 2 {
+				if v, ok := q.TryDequeue(); !ok || v != round*2+i {
+					t.Fatalf("capacity %d: TryDequeue() = %d, %t, want %d, true", capacity, v, ok, round*2+i)
+				}
+			}
+		}
+	}
+}
+
+const benchCapacity = 1024
+
+func BenchmarkMPMCQueue(b *testing.B) {
+	q, _ := deque.MakeMPMCQueue[int](benchCapacity)
+	ctx := context.Background()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			_ = q.Enqueue(ctx, i)
+			_, _ = q.Dequeue(ctx)
+		}
+	})
+}
+
+func BenchmarkChannel(b *testing.B) {
+	ch := make(chan int, benchCapacity)
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			ch <- i
+			<-ch
+		}
+	})
+}
+
+func BenchmarkSyncDeque(b *testing.B) {
+	d, _ := deque.MakeSyncDequeWithCapacity[int](benchCapacity)
+	ctx := context.Background()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			d.PushBack(i)
+			_, _ = d.PopFrontWait(ctx)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package deque_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// TestInsertSortedRing checks the index returned by InsertSorted when a full
+// ring evicts from the front, including when the new element is the one
+// evicted.
+func TestInsertSortedRing(t *testing.T) {
+	for _, tc := range []struct {
+		ring []int
+		t    int
+		want int
+		rest []int
+	}{
+		{[]int{5}, 3, -1, []int{5}},
+		{[]int{5}, 7, 0, []int{7}},
+		{[]int{2, 4, 6}, 1, -1, []int{2, 4, 6}},
+		{[]int{2, 4, 6}, 2, 0, []int{2, 4, 6}},
+		{[]int{2, 4, 6}, 5, 1, []int{4, 5, 6}},
+		{[]int{2, 4, 6}, 8, 2, []int{4, 6, 8}},
+	} {
+		r, err := deque.MakeRing[int](len(tc.ring))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.PushBack(tc.ring...)
+		var evicted []int
+		r.OnEvict(func(v int) { evicted = append(evicted, v) })
+		if got := deque.InsertSorted(r, tc.t); got != tc.want {
+			t.Errorf("InsertSorted(%v, %d) = %d, want %d", tc.ring, tc.t, got, tc.want)
+		}
+		if got := r.MakeSliceCopy(); !slices.Equal(got, tc.rest) {
+			t.Errorf("InsertSorted(%v, %d) left %v, want %v", tc.ring, tc.t, got, tc.rest)
+		}
+		if len(evicted) != 1 {
+			t.Errorf("InsertSorted(%v, %d) evicted %v, want 1 element", tc.ring, tc.t, evicted)
+		}
+	}
+}
+
+// TestRingPush checks that a full ring evicts from the front when pushing to
+// the back, and from the back when pushing to the front, without growing.
+func TestRingPush(t *testing.T) {
+	r, err := deque.MakeRing[int](3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var evicted []int
+	r.OnEvict(func(v int) { evicted = append(evicted, v) })
+	r.PushBack(1, 2, 3, 4, 5)
+	if got := r.MakeSliceCopy(); !slices.Equal(got, []int{3, 4, 5}) || !slices.Equal(evicted, []int{1, 2}) {
+		t.Fatalf("PushBack() left %v and evicted %v, want [3 4 5] and [1 2]", got, evicted)
+	}
+	evicted = nil
+	r.PushFront(6, 7)
+	if got := r.MakeSliceCopy(); !slices.Equal(got, []int{7, 6, 3}) || !slices.Equal(evicted, []int{5, 4}) {
+		t.Fatalf("PushFront() left %v and evicted %v, want [7 6 3] and [5 4]", got, evicted)
+	}
+	if r.Cap() != 4 {
+		t.Fatalf("Cap() = %d after pushing to a ring of 3, want 4", r.Cap())
+	}
+}
+
+// TestRingAtCapacity checks that a ring whose maximum length is a power of two
+// fills its buffer and then evicts instead of reallocating, including when
+// inserting more elements than it holds.
+func TestRingAtCapacity(t *testing.T) {
+	r, _ := deque.MakeRing[int](4)
+	for i := range 4 {
+		if r.Full() {
+			t.Fatalf("Full() = true with %d elements", i)
+		}
+		r.PushBack(i)
+	}
+	if !r.Full() || r.Cap() != 4 {
+		t.Fatalf("Full() = %t with Cap() %d, want true with 4", r.Full(), r.Cap())
+	}
+	var evicted []int
+	r.OnEvict(func(v int) { evicted = append(evicted, v) })
+	r.PushBack(4)
+	r.Insert(3, 9)
+	if got := r.MakeSliceCopy(); !slices.Equal(got, []int{2, 3, 9, 4}) || !slices.Equal(evicted, []int{0, 1}) {
+		t.Fatalf("ring holds %v and evicted %v, want [2 3 9 4] and [0 1]", got, evicted)
+	}
+
+	evicted = nil
+	r.Insert(2, 10, 11, 12, 13, 14, 15)
+	if got := r.MakeSliceCopy(); !slices.Equal(got, []int{14, 15, 9, 4}) {
+		t.Fatalf("Insert() past the maximum length left %v, want [14 15 9 4]", got)
+	}
+	if want := []int{2, 3, 10, 11, 12, 13}; !slices.Equal(evicted, want) {
+		t.Fatalf("Insert() past the maximum length evicted %v, want %v", evicted, want)
+	}
+	if r.Cap() != 4 {
+		t.Fatalf("Cap() = %d after inserting into a full ring, want 4", r.Cap())
+	}
+}
+
+// TestSetMaxLen checks that lowering the maximum length evicts the excess from
+// the front, in order, and that pushes then respect it.
+func TestSetMaxLen(t *testing.T) {
+	d := deque.MakeDeque[int]()
+	for i := range 10 {
+		d.PushBack(i)
+	}
+	var evicted []int
+	d.OnEvict(func(v int) { evicted = append(evicted, v) })
+	if err := d.SetMaxLen(-1); err == nil {
+		t.Fatal("SetMaxLen(-1) succeeded")
+	}
+	if err := d.SetMaxLen(4); err != nil || d.MaxLen() != 4 {
+		t.Fatalf("SetMaxLen(4) = %v with MaxLen() %d", err, d.MaxLen())
+	}
+	if got := d.MakeSliceCopy(); !slices.Equal(got, []int{6, 7, 8, 9}) || !slices.Equal(evicted, []int{0, 1, 2, 3, 4, 5}) {
+		t.Fatalf("SetMaxLen(4) left %v and evicted %v", got, evicted)
+	}
+
+	evicted = nil
+	d.PushBack(10)
+	d.PushFront(5)
+	if got := d.MakeSliceCopy(); !slices.Equal(got, []int{5, 7, 8, 9}) || !slices.Equal(evicted, []int{6, 10}) {
+		t.Fatalf("pushing to the ring left %v and evicted %v, want [5 7 8 9] and [6 10]", got, evicted)
+	}
+	if err := d.SetMaxLen(0); err != nil {
+		t.Fatal(err)
+	}
+	d.PushBack(11)
+	if d.Len() != 5 || len(evicted) != 2 {
+		t.Fatalf("Len() = %d and evicted %v after removing the limit, want 5 and [6 10]", d.Len(), evicted)
+	}
+}
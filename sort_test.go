@@ -0,0 +1,89 @@
+package deque_test
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// TestSortWrapped sorts elements that wrap around the end of the buffer at
+// every offset, and checks the result against the slices package.
+func TestSortWrapped(t *testing.T) {
+	s := []int{5, 1, 4, 1, 3, 5, 2}
+	for off := range wrapCap {
+		d := dequeAt(t, s, off)
+		deque.Sort(d)
+		if got, want := d.MakeSliceCopy(), slices.Sorted(slices.Values(s)); !slices.Equal(got, want) {
+			t.Fatalf("offset %d: Sort() = %v, want %v", off, got, want)
+		}
+		if !deque.IsSorted(d) {
+			t.Fatalf("offset %d: IsSorted() = false after Sort", off)
+		}
+
+		d = dequeAt(t, s, off)
+		desc := func(a, b int) int { return cmp.Compare(b, a) }
+		d.SortFunc(desc)
+		if got, want := d.MakeSliceCopy(), slices.SortedFunc(slices.Values(s), desc); !slices.Equal(got, want) {
+			t.Fatalf("offset %d: SortFunc() = %v, want %v", off, got, want)
+		}
+		if deque.IsSorted(d) || !d.IsSortedFunc(desc) {
+			t.Fatalf("offset %d: IsSorted() and IsSortedFunc() disagree with a descending order", off)
+		}
+	}
+}
+
+// TestSortStableFuncWrapped checks that SortStableFunc keeps equal elements in
+// their original order when they wrap around the end of the buffer.
+func TestSortStableFuncWrapped(t *testing.T) {
+	s := []pair{{2, 0}, {1, 1}, {2, 2}, {0, 3}, {1, 4}, {2, 5}, {0, 6}}
+	want := slices.Clone(s)
+	slices.SortStableFunc(want, cmpKey)
+	for off := range wrapCap {
+		d, _ := deque.MakeDequeWithCapacity[pair](wrapCap)
+		d.PushBack(make([]pair, off)...)
+		d.DropFront(off)
+		d.PushBack(s...)
+		d.SortStableFunc(cmpKey)
+		if got := d.MakeSliceCopy(); !slices.Equal(got, want) {
+			t.Fatalf("offset %d: SortStableFunc() = %v, want %v", off, got, want)
+		}
+	}
+}
+
+// TestSearchWrapped searches sorted elements that wrap around the end of the
+// buffer at every offset, for every target in range and just outside it, and
+// checks the results against the slices and sort packages.
+func TestSearchWrapped(t *testing.T) {
+	s := []int{1, 3, 3, 5, 7, 7, 9}
+	for off := range wrapCap {
+		d := dequeAt(t, s, off)
+		for target := range 11 {
+			i, found := deque.BinarySearch(d, target)
+			wantI, wantFound := slices.BinarySearch(s, target)
+			if i != wantI || found != wantFound {
+				t.Fatalf("offset %d: BinarySearch(%d) = %d, %t, want %d, %t", off, target, i, found, wantI, wantFound)
+			}
+
+			double := func(e, target int) int { return cmp.Compare(2*e, target) }
+			i, found = deque.BinarySearchFunc(d, 2*target, double)
+			wantI, wantFound = slices.BinarySearchFunc(s, 2*target, double)
+			if i != wantI || found != wantFound {
+				t.Fatalf("offset %d: BinarySearchFunc(%d) = %d, %t, want %d, %t", off, 2*target, i, found, wantI, wantFound)
+			}
+
+			// s is sorted, so the partition point is the number of smaller
+			// elements.
+			var want int
+			for _, e := range s {
+				if e < target {
+					want++
+				}
+			}
+			if got := d.PartitionPoint(func(e int) bool { return e < target }); got != want {
+				t.Fatalf("offset %d: PartitionPoint(< %d) = %d, want %d", off, target, got, want)
+			}
+		}
+	}
+}
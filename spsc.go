@@ -0,0 +1,197 @@
+package deque
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+)
+
+// SPSCRing is a lock-free, fixed capacity ring buffer for exactly one producer
+// goroutine and one consumer goroutine. It uses the same power of two buffer
+// and unmasked head and tail counters as Deque, but head is only written by
+// the consumer and tail only by the producer, so neither needs a lock. Each
+// side keeps its counter on its own cache line to avoid false sharing.
+//
+// To create an SPSCRing instance, you must use MakeSPSCRing(cap).
+//
+// Push methods must only be called by the producer, and pop methods only by
+// the consumer. Len and Cap may be called by anyone.
+type SPSCRing[T any] struct {
+	_ cacheLinePad
+	// Consumer side.
+	head      atomic.Uint64
+	tailCache uint64
+	_         cacheLinePad
+	// Producer side.
+	tail      atomic.Uint64
+	headCache uint64
+	_         cacheLinePad
+	// Read-only after construction.
+	buf      []T
+	mask     uint64
+	notEmpty parker
+	notFull  parker
+}
+
+// spinLimit is how many times blocking operations retry, yielding the
+// processor in between, before parking the goroutine.
+const spinLimit = 64
+
+// MakeSPSCRing takes in the desired capacity. Note that if the supplied
+// capacity is not a power of two, it will be increased to the next power of
+// two. The capacity is fixed: pushing to a full SPSCRing fails or blocks.
+// Returns an error if passed a negative value.
+func MakeSPSCRing[T any](capacity int) (*SPSCRing[T], error) {
+	if capacity < 0 {
+		return nil, ErrNegativeCapacity
+	}
+	c := ceilPow2(max(1, uint(capacity)))
+	return &SPSCRing[T]{buf: make([]T, c), mask: uint64(c) - 1}, nil
+}
+
+// Len returns the number of elements in the SPSCRing. By the time the caller
+// inspects the result, the other side may have changed it.
+func (r *SPSCRing[T]) Len() int {
+	h := r.head.Load()
+	t := r.tail.Load()
+	return int(t - h)
+}
+
+// Cap returns the SPSCRing capacity.
+func (r *SPSCRing[T]) Cap() int { return len(r.buf) }
+
+// TryPush puts t at the back of the SPSCRing. If it's full, returns false
+// without blocking.
+func (r *SPSCRing[T]) TryPush(t T) bool {
+	tail := r.tail.Load()
+	if tail-r.headCache == uint64(len(r.buf)) {
+		r.headCache = r.head.Load()
+		if tail-r.headCache == uint64(len(r.buf)) {
+			return false
+		}
+	}
+	r.buf[tail&r.mask] = t
+	r.tail.Store(tail + 1)
+	r.notEmpty.wake()
+	return true
+}
+
+// PushN puts as many elements of ts as fit at the back of the SPSCRing, in
+// order, and returns how many were pushed. It never blocks.
+func (r *SPSCRing[T]) PushN(ts []T) int {
+	tail := r.tail.Load()
+	free := uint64(len(r.buf)) - (tail - r.headCache)
+	if free < uint64(len(ts)) {
+		r.headCache = r.head.Load()
+		free = uint64(len(r.buf)) - (tail - r.headCache)
+	}
+	n := min(free, uint64(len(ts)))
+	if n == 0 {
+		return 0
+	}
+	i := tail & r.mask
+	c := copy(r.buf[i:], ts[:n])
+	copy(r.buf, ts[c:n])
+	r.tail.Store(tail + n)
+	r.notEmpty.wake()
+	return int(n)
+}
+
+// PushWait puts t at the back of the SPSCRing, blocking while it's full. It
+// spins for a while before parking the goroutine. It returns ctx.Err() if the
+// context is done first.
+func (r *SPSCRing[T]) PushWait(ctx context.Context, t T) error {
+	for i := 0; ; i++ {
+		if r.TryPush(t) {
+			return nil
+		}
+		if i < spinLimit {
+			runtime.Gosched()
+			continue
+		}
+		notFull := r.notFull.prepare()
+		if r.TryPush(t) {
+			r.notFull.done()
+			return nil
+		}
+		select {
+		case <-notFull:
+			r.notFull.done()
+		case <-ctx.Done():
+			r.notFull.done()
+			return ctx.Err()
+		}
+	}
+}
+
+// TryPop removes the first element in the SPSCRing, zeroes its slot, and
+// returns it. If it's empty, returns false without blocking.
+func (r *SPSCRing[T]) TryPop() (t T, ok bool) {
+	head := r.head.Load()
+	if head == r.tailCache {
+		r.tailCache = r.tail.Load()
+		if head == r.tailCache {
+			return
+		}
+	}
+	i := head & r.mask
+	t = r.buf[i]
+	var zero T
+	r.buf[i] = zero
+	r.head.Store(head + 1)
+	r.notFull.wake()
+	return t, true
+}
+
+// PopN removes as many elements as are available, up to len(buf), copies them
+// into buf in order, and returns how many were popped. Their slots are zeroed.
+// It never blocks.
+func (r *SPSCRing[T]) PopN(buf []T) int {
+	head := r.head.Load()
+	avail := r.tailCache - head
+	if avail < uint64(len(buf)) {
+		r.tailCache = r.tail.Load()
+		avail = r.tailCache - head
+	}
+	n := min(avail, uint64(len(buf)))
+	if n == 0 {
+		return 0
+	}
+	i := head & r.mask
+	end := min(i+n, uint64(len(r.buf)))
+	c := copy(buf, r.buf[i:end])
+	clear(r.buf[i:end])
+	copy(buf[c:n], r.buf)
+	clear(r.buf[:n-uint64(c)])
+	r.head.Store(head + n)
+	r.notFull.wake()
+	return int(n)
+}
+
+// PopWait removes the first element in the SPSCRing, zeroes its slot, and
+// returns it, blocking while it's empty. It spins for a while before parking
+// the goroutine. It returns ctx.Err() if the context is done first.
+func (r *SPSCRing[T]) PopWait(ctx context.Context) (T, error) {
+	for i := 0; ; i++ {
+		if t, ok := r.TryPop(); ok {
+			return t, nil
+		}
+		if i < spinLimit {
+			runtime.Gosched()
+			continue
+		}
+		notEmpty := r.notEmpty.prepare()
+		if t, ok := r.TryPop(); ok {
+			r.notEmpty.done()
+			return t, nil
+		}
+		select {
+		case <-notEmpty:
+			r.notEmpty.done()
+		case <-ctx.Done():
+			r.notEmpty.done()
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
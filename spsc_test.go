@@ -0,0 +1,135 @@
+package deque_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// TestSPSCRingOrder has a producer and a consumer mix single and batched
+// operations on a small SPSCRing, so batches wrap around the buffer and the
+// cached counters go stale, and checks nothing is lost or reordered.
+func TestSPSCRingOrder(t *testing.T) {
+	const items = 100_000
+	r, err := deque.MakeSPSCRing[int](8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() {
+		buf := make([]int, 5)
+		for next := 0; next < items; {
+			if next%3 == 0 {
+				v, err := r.PopWait(context.Background())
+				if err != nil || v != next {
+					done <- fmt.Errorf("PopWait() = %d, %v, want %d, nil", v, err, next)
+					return
+				}
+				next++
+				continue
+			}
+			n := r.PopN(buf)
+			if n == 0 {
+				runtime.Gosched()
+			}
+			for _, v := range buf[:n] {
+				if v != next {
+					done <- fmt.Errorf("PopN() returned %d, want %d", v, next)
+					return
+				}
+				next++
+			}
+		}
+		done <- nil
+	}()
+
+	batch := make([]int, 7)
+	for i := 0; i < items; {
+		if i%2 == 0 {
+			if err := r.PushWait(context.Background(), i); err != nil {
+				t.Fatal(err)
+			}
+			i++
+			continue
+		}
+		batch = batch[:0]
+		for j := i; j < min(i+7, items); j++ {
+			batch = append(batch, j)
+		}
+		n := r.PushN(batch)
+		if n == 0 {
+			runtime.Gosched()
+		}
+		i += n
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if r.Len() != 0 {
+		t.Fatalf("Len() = %d after popping everything, want 0", r.Len())
+	}
+}
+
+// TestSPSCRingWake checks that parked producers and consumers are woken up by
+// the other side.
+func TestSPSCRingWake(t *testing.T) {
+	r, _ := deque.MakeSPSCRing[int](2)
+	res := make(chan popResult)
+	go func() {
+		v, err := r.PopWait(context.Background())
+		res <- popResult{v, err}
+	}()
+	time.Sleep(blocked)
+	if !r.TryPush(1) {
+		t.Fatal("TryPush() on an empty SPSCRing = false")
+	}
+	if p := <-res; p.err != nil || p.v != 1 {
+		t.Fatalf("PopWait() = %d, %v, want 1, nil", p.v, p.err)
+	}
+
+	if n := r.PushN([]int{2, 3, 4}); n != 2 {
+		t.Fatalf("PushN() of 3 elements into capacity 2 = %d, want 2", n)
+	}
+	pushed := make(chan error)
+	go func() { pushed <- r.PushWait(context.Background(), 4) }()
+	time.Sleep(blocked)
+	if v, ok := r.TryPop(); !ok || v != 2 {
+		t.Fatalf("TryPop() = %d, %t, want 2, true", v, ok)
+	}
+	if err := <-pushed; err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]int, 4)
+	if n := r.PopN(buf); n != 2 || buf[0] != 3 || buf[1] != 4 {
+		t.Fatalf("PopN() = %d, %v, want 2, [3 4]", n, buf[:n])
+	}
+}
+
+func TestSPSCRingWaitContext(t *testing.T) {
+	r, _ := deque.MakeSPSCRing[int](1)
+	ctx, cancel := context.WithTimeout(context.Background(), blocked)
+	defer cancel()
+	if _, err := r.PopWait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("PopWait() on an empty SPSCRing = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if !r.TryPush(1) {
+		t.Fatal("TryPush() on an empty SPSCRing = false")
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	res := make(chan error)
+	go func() { res <- r.PushWait(ctx, 2) }()
+	time.Sleep(blocked)
+	cancel()
+	if err := <-res; !errors.Is(err, context.Canceled) {
+		t.Fatalf("PushWait() on a full SPSCRing = %v, want %v", err, context.Canceled)
+	}
+	if v, ok := r.TryPop(); !ok || v != 1 || r.Len() != 0 {
+		t.Fatalf("TryPop() = %d, %t with Len() %d, want 1, true with Len() 0", v, ok, r.Len())
+	}
+}
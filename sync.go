@@ -0,0 +1,222 @@
+package deque
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// SyncDeque is a Deque guarded by a mutex, safe for concurrent use by multiple
+// goroutines. On top of the non-blocking Deque API, it provides PopFrontWait
+// and PopBackWait, which block until an element is available, the context is
+// done, or the SyncDeque is closed.
+//
+// To create a SyncDeque instance, you must use one of the available
+// constructors, MakeSyncDeque() or MakeSyncDequeWithCapacity(cap).
+//
+// Closing a SyncDeque is similar to closing a channel. Producers must not push
+// after Close, or they panic. Consumers can keep popping the remaining
+// elements, and waiting pops return ErrClosed only once the SyncDeque is both
+// closed and empty.
+type SyncDeque[T any] struct {
+	mu     sync.Mutex
+	d      *Deque[T]
+	ready  notifier
+	closed bool
+}
+
+// MakeSyncDeque allocates a default sized buffer for a SyncDeque.
+func MakeSyncDeque[T any]() *SyncDeque[T] {
+	return &SyncDeque[T]{d: MakeDeque[T]()}
+}
+
+// MakeSyncDequeWithCapacity takes in the desired capacity. It has the same
+// semantics as MakeDequeWithCapacity.
+func MakeSyncDequeWithCapacity[T any](capacity int) (*SyncDeque[T], error) {
+	d, err := MakeDequeWithCapacity[T](capacity)
+	if err != nil {
+		return nil, err
+	}
+	return &SyncDeque[T]{d: d}, nil
+}
+
+// Len returns the number of elements in the SyncDeque. By the time the caller
+// inspects the result, other goroutines may have changed it.
+func (s *SyncDeque[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.d.Len()
+}
+
+// PushBack puts the elements at the back of the SyncDeque and wakes up waiting
+// consumers. It has the same semantics as Deque.PushBack. Panics if the
+// SyncDeque is closed.
+func (s *SyncDeque[T]) PushBack(ts ...T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkOpen()
+	s.d.PushBack(ts...)
+	s.ready.broadcast()
+}
+
+// PushFront puts the elements at the front of the SyncDeque and wakes up
+// waiting consumers. It has the same semantics as Deque.PushFront. Panics if
+// the SyncDeque is closed.
+func (s *SyncDeque[T]) PushFront(ts ...T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkOpen()
+	s.d.PushFront(ts...)
+	s.ready.broadcast()
+}
+
+// PeekBack returns the last element in the SyncDeque. If it's empty, it
+// returns false.
+func (s *SyncDeque[T]) PeekBack() (t T, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.d.PeekBack()
+}
+
+// PeekFront returns the first element in the SyncDeque. If it's empty, it
+// returns false.
+func (s *SyncDeque[T]) PeekFront() (t T, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.d.PeekFront()
+}
+
+// PopBack removes the last element in the SyncDeque and returns it. If it's
+// empty, returns false without blocking. The slot is zeroed, so references
+// held by the element are released.
+func (s *SyncDeque[T]) PopBack() (t T, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.d.PopBackZero()
+}
+
+// PopFront removes the first element in the SyncDeque and returns it. If it's
+// empty, returns false without blocking. The slot is zeroed, so references
+// held by the element are released.
+func (s *SyncDeque[T]) PopFront() (t T, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.d.PopFrontZero()
+}
+
+// PopBackWait removes the last element in the SyncDeque and returns it,
+// blocking while it's empty. It returns ctx.Err() if the context is done
+// first, or ErrClosed if the SyncDeque is closed and empty.
+func (s *SyncDeque[T]) PopBackWait(ctx context.Context) (T, error) {
+	return s.popWait(ctx, (*Deque[T]).PopBackZero)
+}
+
+// PopFrontWait removes the first element in the SyncDeque and returns it,
+// blocking while it's empty. It returns ctx.Err() if the context is done
+// first, or ErrClosed if the SyncDeque is closed and empty.
+func (s *SyncDeque[T]) PopFrontWait(ctx context.Context) (T, error) {
+	return s.popWait(ctx, (*Deque[T]).PopFrontZero)
+}
+
+// Internal implementation for PopBackWait and PopFrontWait.
+func (s *SyncDeque[T]) popWait(ctx context.Context, pop func(*Deque[T]) (T, bool)) (T, error) {
+	s.mu.Lock()
+	for {
+		if t, ok := pop(s.d); ok {
+			s.mu.Unlock()
+			return t, nil
+		}
+		if s.closed {
+			s.mu.Unlock()
+			var zero T
+			return zero, ErrClosed
+		}
+		ready := s.ready.wait()
+		s.mu.Unlock()
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+		s.mu.Lock()
+	}
+}
+
+// Close marks the SyncDeque as closed and wakes up every waiting consumer.
+// Elements already in the SyncDeque can still be popped. Pushing after Close
+// panics. Calling Close more than once is a no-op.
+func (s *SyncDeque[T]) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.ready.broadcast()
+}
+
+// Closed returns whether Close has been called.
+func (s *SyncDeque[T]) Closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+func (s *SyncDeque[T]) checkOpen() {
+	if s.closed {
+		panic("deque: push to closed SyncDeque")
+	}
+}
+
+// notifier is a broadcast primitive that, unlike sync.Cond, can be selected on
+// alongside a context. It must be guarded by its owner's mutex. The channel is
+// allocated lazily, so broadcasting with no waiters is free.
+type notifier struct {
+	ch chan struct{}
+}
+
+// wait returns a channel that is closed on the next broadcast.
+func (n *notifier) wait() <-chan struct{} {
+	if n.ch == nil {
+		n.ch = make(chan struct{})
+	}
+	return n.ch
+}
+
+// broadcast wakes up every goroutine blocked on a channel returned by wait.
+func (n *notifier) broadcast() {
+	if n.ch != nil {
+		close(n.ch)
+		n.ch = nil
+	}
+}
+
+// parker lets goroutines sleep until a lock-free counterpart wakes them up.
+// Waiters register with prepare before rechecking their condition, and wakers
+// call wake after publishing their change, so no wakeup is lost. wake is a
+// single atomic load when nobody is waiting.
+type parker struct {
+	waiters atomic.Int32
+	mu      sync.Mutex
+	n       notifier
+}
+
+// prepare registers a waiter and returns a channel that is closed on the next
+// wake. Every call must be followed by a call to done.
+func (p *parker) prepare() <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.waiters.Add(1)
+	return p.n.wait()
+}
+
+// done unregisters a waiter.
+func (p *parker) done() { p.waiters.Add(-1) }
+
+// wake wakes up every registered waiter.
+func (p *parker) wake() {
+	if p.waiters.Load() != 0 {
+		p.mu.Lock()
+		p.n.broadcast()
+		p.mu.Unlock()
+	}
+}
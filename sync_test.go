@@ -0,0 +1,124 @@
+package deque_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// blocked is how long a test waits to conclude that a call is blocked.
+const blocked = 20 * time.Millisecond
+
+type popResult struct {
+	v   int
+	err error
+}
+
+// TestSyncDequePopWaitBlocks checks that a waiting pop blocks while the
+// SyncDeque is empty, and returns the element pushed afterwards.
+func TestSyncDequePopWaitBlocks(t *testing.T) {
+	s := deque.MakeSyncDeque[int]()
+	res := make(chan popResult)
+	go func() {
+		v, err := s.PopFrontWait(context.Background())
+		res <- popResult{v, err}
+	}()
+
+	select {
+	case r := <-res:
+		t.Fatalf("PopFrontWait() returned %d, %v on an empty SyncDeque", r.v, r.err)
+	case <-time.After(blocked):
+	}
+	s.PushBack(7)
+	if r := <-res; r.err != nil || r.v != 7 {
+		t.Fatalf("PopFrontWait() = %d, %v, want 7, nil", r.v, r.err)
+	}
+}
+
+// TestSyncDequeOrder has a consumer pop everything a producer pushes, and
+// checks nothing is lost or reordered.
+func TestSyncDequeOrder(t *testing.T) {
+	const items = 10_000
+	s := deque.MakeSyncDeque[int]()
+	done := make(chan error)
+	go func() {
+		for want := range items {
+			v, err := s.PopFrontWait(context.Background())
+			if err != nil || v != want {
+				done <- fmt.Errorf("PopFrontWait() = %d, %v, want %d, nil", v, err, want)
+				return
+			}
+		}
+		done <- nil
+	}()
+	for i := range items {
+		s.PushBack(i)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyncDequePopWaitContext(t *testing.T) {
+	s := deque.MakeSyncDeque[int]()
+	ctx, cancel := context.WithTimeout(context.Background(), blocked)
+	defer cancel()
+	if _, err := s.PopBackWait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("PopBackWait() = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	res := make(chan error)
+	go func() {
+		_, err := s.PopFrontWait(ctx)
+		res <- err
+	}()
+	cancel()
+	if err := <-res; !errors.Is(err, context.Canceled) {
+		t.Fatalf("PopFrontWait() = %v, want %v", err, context.Canceled)
+	}
+}
+
+// TestSyncDequeClose checks that Close wakes up waiting consumers, that the
+// remaining elements can still be popped, and that ErrClosed is only returned
+// once the SyncDeque is empty.
+func TestSyncDequeClose(t *testing.T) {
+	s := deque.MakeSyncDeque[int]()
+	res := make(chan popResult)
+	go func() {
+		v, err := s.PopBackWait(context.Background())
+		res <- popResult{v, err}
+	}()
+	time.Sleep(blocked)
+	s.Close()
+	if r := <-res; !errors.Is(r.err, deque.ErrClosed) {
+		t.Fatalf("PopBackWait() after Close = %d, %v, want %v", r.v, r.err, deque.ErrClosed)
+	}
+
+	s = deque.MakeSyncDeque[int]()
+	s.PushBack(1, 2)
+	s.Close()
+	s.Close()
+	if !s.Closed() {
+		t.Fatal("Closed() = false after Close")
+	}
+	for _, want := range []int{1, 2} {
+		if v, err := s.PopFrontWait(context.Background()); err != nil || v != want {
+			t.Fatalf("PopFrontWait() = %d, %v, want %d, nil", v, err, want)
+		}
+	}
+	if _, err := s.PopFrontWait(context.Background()); !errors.Is(err, deque.ErrClosed) {
+		t.Fatalf("PopFrontWait() on closed and empty = %v, want %v", err, deque.ErrClosed)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("PushBack after Close did not panic")
+		}
+	}()
+	s.PushBack(3)
+}
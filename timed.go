@@ -0,0 +1,169 @@
+package deque
+
+import (
+	"iter"
+	"time"
+)
+
+// Clock tells the current time. Inject a fake Clock into a TimedDeque to
+// control expiry in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is a Clock backed by time.Now.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// TimedDeque is a FIFO queue whose elements expire after a time to live, such
+// as the requests of the last minute in a rate limiter. Every push is stamped
+// with a deadline from its Clock, either after a fixed window or after a
+// per-element TTL.
+//
+// Expired elements are removed by Expire, and lazily by every read, so reads
+// never observe them. An optional OnExpire callback is called with every
+// expired element.
+//
+// Expiry is O(1) amortized per element as long as deadlines don't decrease
+// from front to back, which always holds for a fixed window. Pushing an
+// element that expires before the current back makes expiry scan the whole
+// TimedDeque until it's empty again.
+//
+// To create a TimedDeque instance, you must use MakeTimedDeque(window, clock).
+type TimedDeque[T any] struct {
+	d        *Deque[timedEntry[T]]
+	window   time.Duration
+	clock    Clock
+	onExpire func(T)
+	// ordered tracks whether deadlines are non-decreasing from front to back.
+	ordered bool
+}
+
+type timedEntry[T any] struct {
+	t        T
+	deadline time.Time
+}
+
+// MakeTimedDeque takes in the default time to live of its elements, and the
+// Clock used to stamp and expire them. A nil clock means SystemClock. Returns
+// an error if window is not positive.
+func MakeTimedDeque[T any](window time.Duration, clock Clock) (*TimedDeque[T], error) {
+	if window <= 0 {
+		return nil, ErrInvalidWindow
+	}
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &TimedDeque[T]{
+		d:       MakeDeque[timedEntry[T]](),
+		window:  window,
+		clock:   clock,
+		ordered: true,
+	}, nil
+}
+
+// OnExpire registers a function to be called with every expired element, in
+// expiry order. Passing nil unregisters it. f must not modify the TimedDeque.
+func (td *TimedDeque[T]) OnExpire(f func(T)) { td.onExpire = f }
+
+// PushBack puts the elements at the back of the TimedDeque. They expire once
+// the window passed to MakeTimedDeque has elapsed.
+func (td *TimedDeque[T]) PushBack(ts ...T) {
+	deadline := td.clock.Now().Add(td.window)
+	for _, t := range ts {
+		td.push(t, deadline)
+	}
+}
+
+// PushBackTTL puts t at the back of the TimedDeque. It expires once ttl has
+// elapsed, regardless of the window.
+func (td *TimedDeque[T]) PushBackTTL(t T, ttl time.Duration) {
+	td.push(t, td.clock.Now().Add(ttl))
+}
+
+// Expire removes every element whose deadline is not after now, and returns
+// how many were removed.
+func (td *TimedDeque[T]) Expire(now time.Time) int {
+	d := td.d
+	n := d.Len()
+	if td.ordered {
+		for !d.Empty() && !now.Before(d.PeekFrontUnsafe().deadline) {
+			td.expire(d.PopFrontZeroUnsafe().t)
+		}
+	} else {
+		td.compact(now)
+	}
+	if d.Empty() {
+		td.ordered = true
+	}
+	return n - d.Len()
+}
+
+// Len returns the number of unexpired elements in the TimedDeque.
+func (td *TimedDeque[T]) Len() int {
+	td.Expire(td.clock.Now())
+	return td.d.Len()
+}
+
+// PeekFront returns the oldest unexpired element in the TimedDeque. If there
+// are none, it returns false.
+func (td *TimedDeque[T]) PeekFront() (t T, ok bool) {
+	td.Expire(td.clock.Now())
+	e, ok := td.d.PeekFront()
+	return e.t, ok
+}
+
+// PopFront removes the oldest unexpired element in the TimedDeque and returns
+// it. If there are none, it returns false.
+func (td *TimedDeque[T]) PopFront() (t T, ok bool) {
+	td.Expire(td.clock.Now())
+	e, ok := td.d.PopFrontZero()
+	return e.t, ok
+}
+
+// Iter returns an iterator over the unexpired elements in order. Elements are
+// expired once, when iteration starts.
+func (td *TimedDeque[T]) Iter() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		td.Expire(td.clock.Now())
+		for e := range td.d.Iter() {
+			if !yield(e.t) {
+				return
+			}
+		}
+	}
+}
+
+func (td *TimedDeque[T]) push(t T, deadline time.Time) {
+	if back, ok := td.d.PeekBack(); ok && deadline.Before(back.deadline) {
+		td.ordered = false
+	}
+	td.d.PushBack(timedEntry[T]{t: t, deadline: deadline})
+}
+
+// compact removes expired elements anywhere in the TimedDeque, keeping the
+// order of the rest, and checks whether their deadlines are ordered again.
+func (td *TimedDeque[T]) compact(now time.Time) {
+	td.ordered = true
+	var last time.Time
+	td.d.Retain(func(e timedEntry[T]) bool {
+		if !now.Before(e.deadline) {
+			td.expire(e.t)
+			return false
+		}
+		if e.deadline.Before(last) {
+			td.ordered = false
+		}
+		last = e.deadline
+		return true
+	})
+}
+
+func (td *TimedDeque[T]) expire(t T) {
+	if td.onExpire != nil {
+		td.onExpire(t)
+	}
+}
@@ -0,0 +1,117 @@
+package deque_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// fakeClock is a Clock that only moves when told to.
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func makeTimedDeque(t *testing.T, window time.Duration) (*deque.TimedDeque[int], *fakeClock, *[]int) {
+	t.Helper()
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	td, err := deque.MakeTimedDeque[int](window, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expired []int
+	td.OnExpire(func(v int) { expired = append(expired, v) })
+	return td, clock, &expired
+}
+
+// TestTimedDequeWindow checks that elements expire exactly when the window has
+// elapsed since they were pushed.
+func TestTimedDequeWindow(t *testing.T) {
+	td, clock, expired := makeTimedDeque(t, 10*time.Second)
+	td.PushBack(1, 2)
+	clock.advance(4 * time.Second)
+	td.PushBack(3)
+
+	clock.advance(6*time.Second - time.Nanosecond)
+	if td.Len() != 3 {
+		t.Fatalf("Len() = %d just before the window elapsed, want 3", td.Len())
+	}
+	clock.advance(time.Nanosecond)
+	if td.Len() != 1 {
+		t.Fatalf("Len() = %d once the window elapsed, want 1", td.Len())
+	}
+	if v, ok := td.PopFront(); !ok || v != 3 {
+		t.Fatalf("PopFront() = %d, %t, want 3, true", v, ok)
+	}
+	if _, ok := td.PopFront(); ok {
+		t.Fatal("PopFront() on an empty TimedDeque returned true")
+	}
+	if !slices.Equal(*expired, []int{1, 2}) {
+		t.Fatalf("expired %v, want [1 2]", *expired)
+	}
+}
+
+// TestTimedDequeTTL checks that an element pushed with a TTL shorter than the
+// window expires before the elements in front of it, and that OnExpire is
+// called in expiry order.
+func TestTimedDequeTTL(t *testing.T) {
+	td, clock, expired := makeTimedDeque(t, 10*time.Second)
+	td.PushBack(1, 2)
+	clock.advance(5 * time.Second)
+	td.PushBack(3)
+	td.PushBackTTL(4, time.Second)
+	td.PushBack(5)
+	if td.Len() != 5 {
+		t.Fatalf("Len() = %d, want 5", td.Len())
+	}
+
+	clock.advance(time.Second)
+	if got := slices.Collect(td.Iter()); !slices.Equal(got, []int{1, 2, 3, 5}) {
+		t.Fatalf("Iter() = %v after the TTL elapsed, want [1 2 3 5]", got)
+	}
+	clock.advance(4 * time.Second)
+	if v, ok := td.PeekFront(); !ok || v != 3 {
+		t.Fatalf("PeekFront() = %d, %t, want 3, true", v, ok)
+	}
+	if n := td.Expire(clock.now.Add(time.Hour)); n != 2 {
+		t.Fatalf("Expire() = %d, want 2", n)
+	}
+	if want := []int{4, 1, 2, 3, 5}; !slices.Equal(*expired, want) {
+		t.Fatalf("expired %v, want %v", *expired, want)
+	}
+}
+
+// TestTimedDequeLazyExpiry checks that every read expires elements on its own,
+// without calling Expire.
+func TestTimedDequeLazyExpiry(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		read func(*deque.TimedDeque[int]) int
+	}{
+		{"Len", (*deque.TimedDeque[int]).Len},
+		{"PeekFront", func(td *deque.TimedDeque[int]) int {
+			v, _ := td.PeekFront()
+			return v
+		}},
+		{"Iter", func(td *deque.TimedDeque[int]) int {
+			return len(slices.Collect(td.Iter()))
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			td, clock, expired := makeTimedDeque(t, 10*time.Second)
+			td.PushBack(1, 2)
+			clock.advance(5 * time.Second)
+			td.PushBack(1)
+			clock.advance(5 * time.Second)
+			if got := tc.read(td); got != 1 {
+				t.Fatalf("%s() = %d, want 1", tc.name, got)
+			}
+			if !slices.Equal(*expired, []int{1, 2}) {
+				t.Fatalf("expired %v, want [1 2]", *expired)
+			}
+		})
+	}
+}
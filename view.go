@@ -0,0 +1,135 @@
+package deque
+
+import (
+	"fmt"
+	"iter"
+)
+
+// View is a read-only window over a range of a Deque. It doesn't copy or
+// allocate: it reads the elements from the Deque's buffer. Like iterators, a
+// View panics if it's used after the Deque is structurally modified, but it
+// sees writes made with Set or Swap.
+//
+// To create a View, use d.View(start, end).
+type View[T any] struct {
+	d          *Deque[T]
+	start, end uint
+	mod        uint
+}
+
+// View returns a View over the elements from the start index (inclusive) to
+// the end index (non-inclusive). This is regular slice semantics, except the
+// View is read-only. This means it also panics with invalid indexes.
+func (d *Deque[T]) View(start, end int) View[T] {
+	d.checkRange(start, end)
+	return View[T]{
+		d:     d,
+		start: d.head + uint(start),
+		end:   d.head + uint(end),
+		mod:   d.mod,
+	}
+}
+
+// Len returns the number of elements in the View.
+func (v View[T]) Len() int { return int(v.end - v.start) }
+
+// At indexes into the i-th position in the View. Panics if out of bounds.
+func (v View[T]) At(i int) T {
+	v.d.checkMod(v.mod)
+	if i < 0 || i >= v.Len() {
+		panic(fmt.Sprintf("deque: index %d out of bounds with view length %d", i, v.Len()))
+	}
+	return v.d.buf[(v.start+uint(i))&v.d.mask]
+}
+
+// Slices returns the two contiguous parts of the View without copying, with
+// the same semantics as Deque.AsSlices. Writing through them writes to the
+// Deque.
+func (v View[T]) Slices() (a, b []T) {
+	v.d.checkMod(v.mod)
+	return v.d.span(v.start, v.end)
+}
+
+// Iter returns an iterator over the values in the View in order. Panics if the
+// Deque is structurally modified during iteration.
+func (v View[T]) Iter() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s1, s2 := v.Slices()
+		for _, t := range s1 {
+			if !yield(t) {
+				return
+			}
+			v.d.checkMod(v.mod)
+		}
+		for _, t := range s2 {
+			if !yield(t) {
+				return
+			}
+			v.d.checkMod(v.mod)
+		}
+	}
+}
+
+// BlockView is a read-only window over a range of a BlockDeque, like View is
+// for a Deque. Since the range may span many blocks, Blocks yields one slice
+// per block, and takes the place of View's Slices, which returns two.
+//
+// To create a BlockView, use d.View(start, end) on a BlockDeque.
+type BlockView[T any] struct {
+	d          *BlockDeque[T]
+	start, end uint
+	mod        uint
+}
+
+// View returns a BlockView over the elements from the start index (inclusive)
+// to the end index (non-inclusive). This is regular slice semantics, except the
+// BlockView is read-only. This means it also panics with invalid indexes.
+func (d *BlockDeque[T]) View(start, end int) BlockView[T] {
+	d.checkRange(start, end)
+	return BlockView[T]{d: d, start: uint(start), end: uint(end), mod: d.mod}
+}
+
+// Len returns the number of elements in the BlockView.
+func (v BlockView[T]) Len() int { return int(v.end - v.start) }
+
+// At indexes into the i-th position in the BlockView. Panics if out of bounds.
+func (v BlockView[T]) At(i int) T {
+	v.d.checkMod(v.mod)
+	if i < 0 || i >= v.Len() {
+		panic(fmt.Sprintf("deque: index %d out of bounds with view length %d", i, v.Len()))
+	}
+	return *v.d.ptr(v.start + uint(i))
+}
+
+// Blocks returns an iterator over the contiguous parts of the BlockView without
+// copying, with the same semantics as BlockDeque.Blocks. Writing through them
+// writes to the BlockDeque.
+func (v BlockView[T]) Blocks() iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		v.d.checkMod(v.mod)
+		for i := v.start; i < v.end; {
+			s := v.d.span(i)
+			s = s[:min(uint(len(s)), v.end-i)]
+			if !yield(s) {
+				return
+			}
+			v.d.checkMod(v.mod)
+			i += uint(len(s))
+		}
+	}
+}
+
+// Iter returns an iterator over the values in the BlockView in order. Panics if
+// the BlockDeque is structurally modified during iteration.
+func (v BlockView[T]) Iter() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for s := range v.Blocks() {
+			for _, t := range s {
+				if !yield(t) {
+					return
+				}
+				v.d.checkMod(v.mod)
+			}
+		}
+	}
+}
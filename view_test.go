@@ -0,0 +1,60 @@
+package deque_test
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestViewWrapped checks every View of elements that wrap around the end of
+// the buffer, at every offset, against the same range of a slice.
+func TestViewWrapped(t *testing.T) {
+	s := []int{10, 11, 12, 13, 14, 15, 16}
+	for off := range wrapCap {
+		d := dequeAt(t, s, off)
+		for i := range len(s) + 1 {
+			for j := i; j <= len(s); j++ {
+				v := d.View(i, j)
+				want := s[i:j]
+				if v.Len() != len(want) {
+					t.Fatalf("offset %d: View(%d, %d).Len() = %d, want %d", off, i, j, v.Len(), len(want))
+				}
+				for k, w := range want {
+					if got := v.At(k); got != w {
+						t.Fatalf("offset %d: View(%d, %d).At(%d) = %d, want %d", off, i, j, k, got, w)
+					}
+				}
+				a, b := v.Slices()
+				if got := slices.Concat(a, b); !slices.Equal(got, want) || len(a) == 0 && len(b) != 0 {
+					t.Fatalf("offset %d: View(%d, %d).Slices() = %v, %v, want %v", off, i, j, a, b, want)
+				}
+				if got := slices.Collect(v.Iter()); !slices.Equal(got, want) {
+					t.Fatalf("offset %d: View(%d, %d).Iter() = %v, want %v", off, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+// TestViewPanics checks that View panics with invalid indexes, and that a View
+// panics once the Deque is structurally modified, but sees writes.
+func TestViewPanics(t *testing.T) {
+	d := dequeAt(t, []int{0, 1, 2, 3}, 6)
+	mustPanic(t, "View(-1, 2)", func() { d.View(-1, 2) })
+	mustPanic(t, "View(3, 2)", func() { d.View(3, 2) })
+	mustPanic(t, "View(0, 5)", func() { d.View(0, 5) })
+
+	v := d.View(1, 3)
+	mustPanic(t, "At(-1)", func() { v.At(-1) })
+	mustPanic(t, "At(2)", func() { v.At(2) })
+	d.Set(2, 20)
+	if v.At(1) != 20 {
+		t.Fatalf("At(1) = %d after Set, want 20", v.At(1))
+	}
+	d.PushBack(4)
+	mustPanic(t, "At after PushBack", func() { v.At(0) })
+	mustPanic(t, "Slices after PushBack", func() { v.Slices() })
+	mustPanic(t, "Iter after PushBack", func() {
+		for range v.Iter() {
+		}
+	})
+}
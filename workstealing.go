@@ -0,0 +1,147 @@
+package deque
+
+import "sync/atomic"
+
+// WorkStealingDeque is a lock-free Chase-Lev work-stealing deque. A single
+// owner goroutine pushes and pops at the bottom, LIFO, while any number of
+// thief goroutines steal from the top, FIFO. This is the building block of
+// work-stealing schedulers: owners keep their hot tasks local, and idle
+// workers take the oldest ones.
+//
+// To create a WorkStealingDeque instance, you must use one of the available
+// constructors, MakeWorkStealingDeque() or
+// MakeWorkStealingDequeWithCapacity(cap).
+//
+// Like Deque, it uses a power of two buffer indexed by monotonically
+// increasing counters, growing to twice the size when it overflows. It never
+// shrinks. Elements are boxed so that thieves can read them atomically, which
+// costs one allocation per Push. Slots are not cleared after a Steal, so
+// references may be retained until the slot is reused.
+type WorkStealingDeque[T any] struct {
+	top    atomic.Int64
+	_      cacheLinePad
+	bottom atomic.Int64
+	buf    atomic.Pointer[wsBuffer[T]]
+}
+
+type wsBuffer[T any] struct {
+	slots []atomic.Pointer[T]
+	mask  int64
+}
+
+// MakeWorkStealingDeque allocates a default sized buffer for a
+// WorkStealingDeque.
+func MakeWorkStealingDeque[T any]() *WorkStealingDeque[T] {
+	const defaultCapacity = 16
+	d, _ := MakeWorkStealingDequeWithCapacity[T](defaultCapacity)
+	return d
+}
+
+// MakeWorkStealingDequeWithCapacity takes in the desired capacity. Note that
+// if the supplied capacity is not a power of two, it will be increased to the
+// next power of two. Returns an error if passed a negative value.
+func MakeWorkStealingDequeWithCapacity[T any](capacity int) (*WorkStealingDeque[T], error) {
+	if capacity < 0 {
+		return nil, ErrNegativeCapacity
+	}
+	d := &WorkStealingDeque[T]{}
+	d.buf.Store(newWSBuffer[T](ceilPow2(max(1, uint(capacity)))))
+	return d, nil
+}
+
+// Len returns an estimate of the number of elements in the WorkStealingDeque.
+// It is exact only when no goroutine is concurrently pushing, popping, or
+// stealing.
+func (d *WorkStealingDeque[T]) Len() int {
+	b := d.bottom.Load()
+	t := d.top.Load()
+	return int(max(0, b-t))
+}
+
+// Empty returns whether the WorkStealingDeque appears empty. It has the same
+// caveats as Len.
+func (d *WorkStealingDeque[T]) Empty() bool { return d.Len() == 0 }
+
+// Push puts t at the bottom of the WorkStealingDeque, growing the buffer if
+// it's full. It must only be called by the owner goroutine.
+func (d *WorkStealingDeque[T]) Push(t T) {
+	b := d.bottom.Load()
+	top := d.top.Load()
+	a := d.buf.Load()
+	if b-top > a.mask {
+		a = a.grow(top, b)
+		d.buf.Store(a)
+	}
+	a.put(b, &t)
+	d.bottom.Store(b + 1)
+}
+
+// Pop removes the element at the bottom of the WorkStealingDeque and returns
+// it. If it's empty, or a thief won the race for the last element, returns
+// false. It must only be called by the owner goroutine.
+func (d *WorkStealingDeque[T]) Pop() (t T, ok bool) {
+	b := d.bottom.Load() - 1
+	a := d.buf.Load()
+	d.bottom.Store(b)
+	top := d.top.Load()
+
+	if top > b {
+		// Empty: restore bottom.
+		d.bottom.Store(b + 1)
+		return
+	}
+
+	p := a.get(b)
+	if top == b {
+		// Last element: race thieves for it.
+		won := d.top.CompareAndSwap(top, top+1)
+		d.bottom.Store(b + 1)
+		if !won {
+			return
+		}
+		return *p, true
+	}
+
+	// Thieves can no longer reach this slot, so release the reference.
+	a.put(b, nil)
+	return *p, true
+}
+
+// Steal removes the element at the top of the WorkStealingDeque and returns
+// it. If it's empty, returns false. It may be called by any goroutine.
+func (d *WorkStealingDeque[T]) Steal() (t T, ok bool) {
+	for {
+		top := d.top.Load()
+		b := d.bottom.Load()
+		if top >= b {
+			return
+		}
+
+		// Read before claiming: once top moves, the owner may reuse the slot.
+		p := d.buf.Load().get(top)
+		if d.top.CompareAndSwap(top, top+1) {
+			return *p, true
+		}
+		// Another thief or the owner claimed it first, so try the next one.
+	}
+}
+
+func newWSBuffer[T any](capacity uint) *wsBuffer[T] {
+	return &wsBuffer[T]{
+		slots: make([]atomic.Pointer[T], capacity),
+		mask:  int64(capacity) - 1,
+	}
+}
+
+func (a *wsBuffer[T]) get(i int64) *T    { return a.slots[i&a.mask].Load() }
+func (a *wsBuffer[T]) put(i int64, p *T) { a.slots[i&a.mask].Store(p) }
+
+// grow returns a buffer twice the size holding the elements in [top, bottom).
+// The old buffer is left untouched, as thieves may still be reading from it.
+func (a *wsBuffer[T]) grow(top, bottom int64) *wsBuffer[T] {
+	g := newWSBuffer[T](uint(len(a.slots)) << 1)
+	for i := top; i < bottom; i++ {
+		g.put(i, a.get(i))
+	}
+	return g
+}
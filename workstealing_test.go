@@ -0,0 +1,95 @@
+package deque_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lucasgdosr/deque"
+)
+
+// TestWorkStealingDequeStress has an owner push and pop while thieves steal,
+// then checks every item was taken exactly once.
+func TestWorkStealingDequeStress(t *testing.T) {
+	const (
+		items   = 100_000
+		thieves = 4
+	)
+	d, err := deque.MakeWorkStealingDequeWithCapacity[int](2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var done atomic.Bool
+	var wg sync.WaitGroup
+	taken := make([][]int, thieves+1)
+	for w := range thieves {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !done.Load() || !d.Empty() {
+				if v, ok := d.Steal(); ok {
+					taken[w] = append(taken[w], v)
+				}
+			}
+		}()
+	}
+
+	owner := &taken[thieves]
+	for i := range items {
+		d.Push(i)
+		if i%3 == 0 {
+			if v, ok := d.Pop(); ok {
+				*owner = append(*owner, v)
+			}
+		}
+	}
+	for {
+		v, ok := d.Pop()
+		if !ok {
+			break
+		}
+		*owner = append(*owner, v)
+	}
+	done.Store(true)
+	wg.Wait()
+
+	seen := make([]int, items)
+	for _, vs := range taken {
+		for _, v := range vs {
+			seen[v]++
+		}
+	}
+	for v, n := range seen {
+		if n != 1 {
+			t.Fatalf("item %d taken %d times", v, n)
+		}
+	}
+}
+
+func TestWorkStealingDequeOrder(t *testing.T) {
+	d := deque.MakeWorkStealingDeque[int]()
+	for i := range 100 {
+		d.Push(i)
+	}
+	if v, ok := d.Pop(); !ok || v != 99 {
+		t.Fatalf("Pop() = %d, %t; want 99, true", v, ok)
+	}
+	if v, ok := d.Steal(); !ok || v != 0 {
+		t.Fatalf("Steal() = %d, %t; want 0, true", v, ok)
+	}
+	if n := d.Len(); n != 98 {
+		t.Fatalf("Len() = %d; want 98", n)
+	}
+	for range 98 {
+		if _, ok := d.Pop(); !ok {
+			t.Fatal("Pop() on non-empty deque returned false")
+		}
+	}
+	if _, ok := d.Pop(); ok {
+		t.Fatal("Pop() on empty deque returned true")
+	}
+	if _, ok := d.Steal(); ok {
+		t.Fatal("Steal() on empty deque returned true")
+	}
+}